@@ -0,0 +1,164 @@
+package unit
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Absolute is returned (wrapped) by MulErr, DivErr and PowErr when one of
+// their operands is an absolute reading on an affine scale.
+var Absolute = errors.New("absolute reading")
+
+// affineType is a unit whose zero point is offset from the zero point of
+// its primitive units, such as degrees Celsius.  A reading of x in an
+// affine unit corresponds to x+offset in its delta unit, which is an
+// ordinary (linear) unit describing differences on the same scale.
+type affineType struct {
+	symbol string
+	delta  Unit
+	offset float64
+	units  Units
+}
+
+func (u *affineType) Symbol() string       { return u.symbol }
+func (u *affineType) Deriv() Value         { return u.delta.Make(1) }
+func (u *affineType) Units() Units         { return u.units }
+func (u *affineType) Value() float64       { return 1 }
+func (u *affineType) Make(v float64) Value { return u.units.Make(v) }
+func (u *affineType) Equal(o Unit) bool    { return unitEqual(u, o) }
+func (u *affineType) dims() dims           { return unitDims(u.delta) }
+func (u *affineType) String() string {
+	return fmt.Sprintf("Affine(%q = %v%+g)", u.symbol, u.delta, u.offset)
+}
+
+// Affine creates a Maker associated with a new unit named symbol, whose
+// values are absolute readings on a scale with the same increments as
+// delta, but whose zero is offset by offset units of delta from the zero
+// of delta's primitive units.  For instance, degrees Celsius is an affine
+// unit with an offset of 273.15 on top of a delta unit equal to 1 K.
+//
+// Values are treated as absolute readings only when their units consist of
+// a single affine unit.  Convert applies the offset when converting such
+// a value to a single unit (affine or not), so that 20 °C converts to
+// 68 °F or 293.15 K.  Products and powers of absolute readings make no
+// physical sense: Mul, Div and Pow treat them as differences, replacing
+// the affine unit with its delta unit, so that 20 °C times 2 gives
+// 40 Δ°C, while MulErr, DivErr and PowErr reject them with an error
+// wrapping Absolute.  Use AsDelta to make the conversion explicit.  Add
+// and Sub follow the rules for absolute readings; see Value.Add and
+// Value.Sub.
+//
+// Delta must be a Maker for a single named unit.
+func Affine(symbol string, delta Maker, offset float64) Maker {
+	du := delta.Unit()
+	if du == nil {
+		panic(fmt.Sprintf("affine unit %q must be based on a singular unit, got %q", symbol, delta.Units()))
+	}
+	u := &affineType{
+		symbol: symbol,
+		delta:  du,
+		offset: offset,
+	}
+	u.units = Units{N: []Unit{u}}
+	return u.Make
+}
+
+// absolute returns the affine unit if us consists of a single affine unit
+// in its numerator, or nil otherwise.
+func (us Units) absolute() *affineType {
+	if len(us.N) == 1 && len(us.D) == 0 {
+		if u, ok := us.N[0].(*affineType); ok {
+			return u
+		}
+	}
+	return nil
+}
+
+// IsAbsolute returns true if v is an absolute reading on an affine scale,
+// such as "20 °C", rather than a difference or a linear quantity.
+func (v Value) IsAbsolute() bool {
+	return v.U.absolute() != nil
+}
+
+// AsDelta returns v with an absolute reading's affine unit replaced by its
+// delta unit, without applying the scale's offset, so that 20 °C becomes
+// 20 Δ°C.  Other values are returned unchanged.
+func (v Value) AsDelta() Value {
+	v.U = v.U.relative()
+	return v
+}
+
+// relative returns the delta units of us if it is an absolute reading, or
+// us otherwise.
+func (us Units) relative() Units {
+	if a := us.absolute(); a != nil {
+		return a.delta.Units()
+	}
+	return us
+}
+
+// relativize replaces affine units in us with their delta units, unless us
+// is an absolute reading.
+func (us *Units) relativize() {
+	if us.absolute() != nil {
+		return
+	}
+	for _, l := range [][]Unit{us.N, us.D} {
+		for i, u := range l {
+			switch u := u.(type) {
+			case *affineType:
+				l[i] = u.delta
+			case *rootType:
+				if a, ok := u.inner.(*affineType); ok {
+					l[i] = newRoot(a.delta, u.den)
+				}
+			}
+		}
+	}
+}
+
+// hasAffine returns true if us contains any affine units, or roots of
+// them.
+func (us Units) hasAffine() bool {
+	for _, l := range [][]Unit{us.N, us.D} {
+		for _, u := range l {
+			switch u := u.(type) {
+			case *affineType:
+				return true
+			case *rootType:
+				if _, ok := u.inner.(*affineType); ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Delta returns the units used to describe differences of v.  Absolute
+// readings on an affine scale return the scale's delta unit; other values
+// return their own units.
+func (v Value) Delta() Units {
+	return v.U.relative()
+}
+
+// offsets returns the offsets that apply when converting a value from
+// units a to units b.  Both are zero unless one of them is an absolute
+// affine unit and the other a single unit.
+func offsets(a, b Units) (from, to float64) {
+	aa, ba := a.absolute(), b.absolute()
+	if aa == nil && ba == nil {
+		return 0, 0
+	}
+	single := func(us Units) bool { return len(us.N) == 1 && len(us.D) == 0 }
+	if !single(a) || !single(b) {
+		return 0, 0
+	}
+	if aa != nil {
+		from = aa.offset
+	}
+	if ba != nil {
+		to = ba.offset
+	}
+	return from, to
+}
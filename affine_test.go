@@ -0,0 +1,149 @@
+package unit_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dnesting/unit"
+)
+
+func TestAffineConvert(t *testing.T) {
+	k := unit.Primitive("K")
+	dc := unit.Derive("Δ°C", k)
+	c := unit.Affine("°C", dc, 273.15)
+	df := unit.Derive("Δ°F", k(5.0/9))
+	f := unit.Affine("°F", df, 459.67)
+
+	for _, x := range []struct {
+		a        unit.Value
+		to       unit.Maker
+		expected unit.Value
+	}{
+		{c(20), f, f(68)},
+		{f(68), c, c(20)},
+		{c(-40), f, f(-40)},
+		{c(0), k, k(273.15)},
+		{k(0), f, f(-459.67)},
+		{dc(10), df, df(18)},
+		{dc(10), k, k(10)},
+	} {
+		r, remain := x.a.Convert(x.to)
+		if !remain.Empty() || !r.Approx(x.expected, 0.000001) || !r.Units().Equal(x.expected.Units()) {
+			t.Errorf("%q.Convert(%v) should give %q, got %q (remain=%q)", x.a, x.to, x.expected, r, remain)
+		}
+	}
+
+	if !c(100).Equal(k(373.15)) {
+		t.Errorf("%q should equal %q", c(100), k(373.15))
+	}
+	if !c(20).Approx(f(68), 0.000001) {
+		t.Errorf("%q should equal %q", c(20), f(68))
+	}
+}
+
+func TestAffineArithmetic(t *testing.T) {
+	k := unit.Primitive("K")
+	dc := unit.Derive("Δ°C", k)
+	c := unit.Affine("°C", dc, 273.15)
+	df := unit.Derive("Δ°F", k(5.0/9))
+	f := unit.Affine("°F", df, 459.67)
+
+	if r, ok := c(30).Sub(c(20)); !ok || !r.Approx(dc(10), 0.000001) || !r.Units().Equal(dc.Units()) {
+		t.Errorf("30 °C - 20 °C should be 10 Δ°C, got %q (ok=%v)", r, ok)
+	}
+	if r, ok := c(20).Sub(f(32)); !ok || !r.Approx(dc(20), 0.000001) {
+		t.Errorf("20 °C - 32 °F should be 20 Δ°C, got %q (ok=%v)", r, ok)
+	}
+	if r, ok := c(20).Add(df(18)); !ok || !r.Approx(c(30), 0.000001) || !r.IsAbsolute() {
+		t.Errorf("20 °C + 18 Δ°F should be 30 °C, got %q (ok=%v)", r, ok)
+	}
+	if r, ok := k(5).Add(c(20)); !ok || !r.Approx(c(25), 0.000001) || !r.IsAbsolute() {
+		t.Errorf("5 K + 20 °C should be 25 °C, got %q (ok=%v)", r, ok)
+	}
+	if r, ok := c(20).Sub(dc(5)); !ok || !r.Approx(c(15), 0.000001) || !r.IsAbsolute() {
+		t.Errorf("20 °C - 5 Δ°C should be 15 °C, got %q (ok=%v)", r, ok)
+	}
+	if r, ok := c(20).Add(c(20)); ok {
+		t.Errorf("20 °C + 20 °C should fail, got %q", r)
+	}
+	if r, ok := dc(20).Sub(c(20)); ok {
+		t.Errorf("20 Δ°C - 20 °C should fail, got %q", r)
+	}
+
+	j := unit.Primitive("J")
+	cap := j.Div(c)(4.2)
+	if e := j.Div(dc).Units(); !cap.Units().Equal(e) {
+		t.Errorf("J/°C should be expressed as %q, got %q", e, cap.Units())
+	}
+	if sq := c(2).Mul(c(3)); sq.IsAbsolute() || !sq.Units().Equal(dc.Pow(2).Units()) {
+		t.Errorf("°C squared should be expressed as %q, got %q", dc.Pow(2).Units(), sq)
+	}
+	if r := c(20).MulN(2); !r.IsAbsolute() {
+		t.Errorf("%q should remain absolute", r)
+	}
+}
+
+func TestAffineProducts(t *testing.T) {
+	k := unit.Primitive("K")
+	dc := unit.Derive("Δ°C", k)
+	c := unit.Affine("°C", dc, 273.15)
+	m := unit.Primitive("m")
+
+	for _, x := range []struct {
+		name     string
+		got      unit.Value
+		expected unit.Value
+	}{
+		{"20 °C * 2 m", c(20).Mul(m(2)), dc.Mul(m)(40)},
+		{"20 °C * 20 °C", c(20).Mul(c(20)), dc.Pow(2)(400)},
+		{"20 °C * 2", c(20).Mul(unit.Value{S: 2}), dc(40)},
+		{"2 * 20 °C", unit.Value{S: 2}.Mul(c(20)), dc(40)},
+		{"20 °C / 2", c(20).Div(unit.Value{S: 2}), dc(10)},
+		{"20 °C ^ 2", c(20).Pow(2), dc.Pow(2)(400)},
+		{"20 °C ^ 1", c(20).Pow(1), c(20)},
+	} {
+		if !x.got.Equal(x.expected) || !x.got.U.Equal(x.expected.U) {
+			t.Errorf("%s should give %q, got %q", x.name, x.expected, x.got)
+		}
+	}
+
+	for _, x := range []struct {
+		name string
+		fn   func() (unit.Value, error)
+	}{
+		{"20 °C * 2 m", func() (unit.Value, error) { return c(20).MulErr(m(2)) }},
+		{"2 m * 20 °C", func() (unit.Value, error) { return m(2).MulErr(c(20)) }},
+		{"20 °C * 20 °C", func() (unit.Value, error) { return c(20).MulErr(c(20)) }},
+		{"20 °C * 2", func() (unit.Value, error) { return c(20).MulErr(unit.Value{S: 2}) }},
+		{"20 °C / 2", func() (unit.Value, error) { return c(20).DivErr(unit.Value{S: 2}) }},
+		{"1 / 20 °C", func() (unit.Value, error) { return unit.Value{S: 1}.DivErr(c(20)) }},
+		{"20 °C ^ 2", func() (unit.Value, error) { return c(20).PowErr(2) }},
+	} {
+		if r, err := x.fn(); !errors.Is(err, unit.Absolute) {
+			t.Errorf("%s should fail with Absolute, got %q (err=%v)", x.name, r, err)
+		}
+	}
+
+	if r, err := c(20).AsDelta().MulErr(m(2)); err != nil || !r.Equal(dc.Mul(m)(40)) {
+		t.Errorf("20 Δ°C * 2 m should give 40 m Δ°C, got %q (err=%v)", r, err)
+	}
+	if r, err := c(20).PowErr(1); err != nil || !r.IsAbsolute() {
+		t.Errorf("20 °C ^ 1 should give 20 °C, got %q (err=%v)", r, err)
+	}
+}
+
+func ExampleAffine() {
+	k := unit.Primitive("K")
+	c := unit.Affine("°C", unit.Derive("Δ°C", k), 273.15)
+	f := unit.Affine("°F", unit.Derive("Δ°F", k(5.0/9)), 459.67)
+
+	t, _ := c(20).Convert(f)
+	fmt.Printf("%.4g %v\n", t, t.Units())
+
+	d, _ := c(25).Sub(f(68))
+	fmt.Printf("%.4g %v\n", d, d.Units())
+	// Output:
+	// 68 °F
+	// 5 Δ°C
+}
@@ -0,0 +1,225 @@
+package unit_test
+
+import (
+	"testing"
+
+	"github.com/dnesting/unit"
+)
+
+func BenchmarkEquiv(b *testing.B) {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+	kg := unit.Primitive("kg")
+	a := unit.Primitive("A")
+	v := unit.Derive("V", unit.Derive("W", kg.Mul(m.Pow(2)).Div(s.Pow(3))).Div(a))
+	x := v.Units()
+	y := kg.Mul(m.Pow(2)).Div(s.Pow(3)).Units()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Equiv(y)
+	}
+}
+
+func BenchmarkConvert(b *testing.B) {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+	kg := unit.Primitive("kg")
+	a := unit.Primitive("A")
+	v := unit.Derive("V", unit.Derive("W", kg.Mul(m.Pow(2)).Div(s.Pow(3))).Div(a))
+	mv := unit.Derive("mV", v(0.001))
+	x := v(1.5)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Convert(mv)
+	}
+}
+
+func BenchmarkEqual(b *testing.B) {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+	kg := unit.Primitive("kg")
+	a := unit.Primitive("A")
+	v := unit.Derive("V", unit.Derive("W", kg.Mul(m.Pow(2)).Div(s.Pow(3))).Div(a))
+	mv := unit.Derive("mV", v(0.001))
+	x := v(1.5)
+	y := mv(1500)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Equal(y)
+	}
+}
+
+func BenchmarkConverter(b *testing.B) {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+	kg := unit.Primitive("kg")
+	a := unit.Primitive("A")
+	v := unit.Derive("V", unit.Derive("W", kg.Mul(m.Pow(2)).Div(s.Pow(3))).Div(a))
+	mv := unit.Derive("mV", v(0.001))
+	c, err := unit.NewConverter(v, mv)
+	if err != nil {
+		b.Fatal(err)
+	}
+	xs := make([]float64, 1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.ConvertSlice(xs)
+	}
+}
+
+func BenchmarkCompare(b *testing.B) {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+	kg := unit.Primitive("kg")
+	a := unit.Primitive("A")
+	v := unit.Derive("V", unit.Derive("W", kg.Mul(m.Pow(2)).Div(s.Pow(3))).Div(a))
+	mv := unit.Derive("mV", v(0.001))
+	x := v(1.5)
+	y := mv(1600)
+	less := func(a, b float64) bool { return a < b }
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Compare(y, less)
+	}
+}
+
+func BenchmarkReduce(b *testing.B) {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+	kg := unit.Primitive("kg")
+	a := unit.Primitive("A")
+	v := unit.Derive("V", unit.Derive("W", kg.Mul(m.Pow(2)).Div(s.Pow(3))).Div(a))
+	x := v.Div(m).Mul(s).Units()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Reduce()
+	}
+}
+
+func BenchmarkReduceUnit(b *testing.B) {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+	kg := unit.Primitive("kg")
+	a := unit.Primitive("A")
+	v := unit.Derive("V", unit.Derive("W", kg.Mul(m.Pow(2)).Div(s.Pow(3))).Div(a))
+	x := v.Units()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Reduce()
+	}
+}
+
+func BenchmarkMul(b *testing.B) {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+	x := m(1.5)
+	y := s(2)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Mul(y)
+	}
+}
+
+func BenchmarkMulUnitless(b *testing.B) {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+	x := m.Div(s)(1.5)
+	y := unit.Value{S: 2}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Mul(y)
+	}
+}
+
+func BenchmarkMulSame(b *testing.B) {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+	x := m.Div(s)(1.5)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Mul(x)
+	}
+}
+
+func BenchmarkDiv(b *testing.B) {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+	x := m(1.5)
+	y := s(2)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Div(y)
+	}
+}
+
+func BenchmarkDivSame(b *testing.B) {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+	x := m.Div(s)(1.5)
+	y := m.Div(s)(3)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Div(y)
+	}
+}
+
+func BenchmarkPow(b *testing.B) {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+	x := m.Div(s)(1.5)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Pow(3)
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	var r unit.Registry
+	r.Primitive("m")
+	r.Primitive("s")
+	r.Prefix("k", 1000)
+	r.Primitive("g")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := unit.Parse("1.234 kg m/s^2", &r, true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFormat(b *testing.B) {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+	kg := unit.Primitive("kg")
+	x := kg.Mul(m).Div(s.Pow(2))(1.234)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = x.String()
+	}
+}
+
+func BenchmarkFindParallel(b *testing.B) {
+	r := unit.NewRegistry("", nil)
+	r.Primitive("m")
+	r.Prefix("k", 1000)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			r.Find("km")
+		}
+	})
+}
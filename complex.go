@@ -0,0 +1,136 @@
+package unit
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Complex is a complex value with associated units, such as an impedance
+// or a phasor voltage.  Complex values are assumed to be in linear units;
+// offsets of affine units are not applied when they are converted.
+type Complex struct {
+	C complex128
+	U Units
+}
+
+// Complex creates a new complex value of c in m's units.
+func (m Maker) Complex(c complex128) Complex {
+	v := m(1)
+	return Complex{C: c * complex(v.S, 0), U: v.U}
+}
+
+// Polar creates a new complex value with the magnitude of mag and the
+// phase angle phase, in radians.
+func Polar(mag Value, phase float64) Complex {
+	return Complex{C: cmplx.Rect(mag.S, phase), U: mag.U}
+}
+
+// Units returns the Units for c.
+func (c Complex) Units() Units { return c.U }
+
+// Real returns the real part of c.
+func (c Complex) Real() Value { return Value{S: real(c.C), U: c.U} }
+
+// Imag returns the imaginary part of c.
+func (c Complex) Imag() Value { return Value{S: imag(c.C), U: c.U} }
+
+// Abs returns the magnitude of c.
+func (c Complex) Abs() Value { return Value{S: cmplx.Abs(c.C), U: c.U} }
+
+// Phase returns the phase angle of c, in radians, in the range [-π, π].
+func (c Complex) Phase() float64 { return cmplx.Phase(c.C) }
+
+// Conj returns the complex conjugate of c.
+func (c Complex) Conj() Complex { return Complex{C: cmplx.Conj(c.C), U: c.U} }
+
+func (c Complex) String() string {
+	return DefaultFormatter.FormatComplex(c)
+}
+
+// Convert converts c into the units of wanted, as described in
+// Value.Convert.  Both the real and imaginary parts are scaled by the same
+// factor.  Logarithmic units (see Logarithmic) cannot be converted by a
+// factor, and so are not conformable with any others.
+func (c Complex) Convert(wanted Maker) (result Complex, remain Units) {
+	wu := wanted.Units()
+	if c.U.Equal(wu) {
+		return c, Units{}
+	}
+	f, u, remain := linearFactor(c.U, wu)
+	return Complex{C: c.C * complex(f, 0), U: u}, remain
+}
+
+// linearFactor returns the factor by which scalars in the units from are
+// multiplied to convert them into the units to, treating affine units as
+// their delta units, along with the units of the result.  If the units are
+// not conformable, or either is logarithmic and so not related to the
+// other by a factor, remain is non-empty, as described in Value.Convert.
+func linearFactor(from, to Units) (f float64, u Units, remain Units) {
+	if from.logarithmic() != nil || to.logarithmic() != nil {
+		// As for a difference in kind, the units are not cancelled, so
+		// that the remainder is not empty.
+		return 1, to, kindRemainder(from, to)
+	}
+	d := from.dims().div(to.dims())
+	if len(d.t) > 0 {
+		return d.f, to, d.units()
+	}
+	if !kindsAgree(from, to) {
+		return d.f, to, kindRemainder(from, to)
+	}
+	u = to
+	if to.Kind() == nil {
+		u.K = from.K
+	}
+	return d.f, u, Units{}
+}
+
+// Add adds a and b, returning the result in a's units.  If the units are
+// not equivalent, r will be empty and ok will be false.
+func (a Complex) Add(b Complex) (r Complex, ok bool) {
+	if b, ok = a.conform(b); ok {
+		r = Complex{C: a.C + b.C, U: a.U}
+	}
+	return
+}
+
+// Sub subtracts b from a, returning the result in a's units.  If the units
+// are not equivalent, r will be empty and ok will be false.
+func (a Complex) Sub(b Complex) (r Complex, ok bool) {
+	if b, ok = a.conform(b); ok {
+		r = Complex{C: a.C - b.C, U: a.U}
+	}
+	return
+}
+
+func (a Complex) conform(b Complex) (Complex, bool) {
+	if a.U.Equal(b.U) {
+		return b, true
+	}
+	b, remain := b.Convert(a.U.Make)
+	return b, remain.Empty()
+}
+
+// Mul multiplies a and b, returning the result.
+func (a Complex) Mul(b Complex) Complex {
+	return Complex{C: a.C * b.C, U: a.U.Mul(b.U)}
+}
+
+// MulV multiplies a by the real value b, returning the result.
+func (a Complex) MulV(b Qualified) Complex {
+	return Complex{C: a.C * complex(b.Value(), 0), U: a.U.Mul(b.Units())}
+}
+
+// Div divides a by b, returning the result.
+func (a Complex) Div(b Complex) Complex {
+	return Complex{C: a.C / b.C, U: a.U.Div(b.U)}
+}
+
+// Recip returns the reciprocal of c, such as the admittance corresponding
+// to an impedance.
+func (c Complex) Recip() Complex {
+	return Complex{C: 1 / c.C, U: c.U.Recip()}
+}
+
+// degrees converts an angle in radians to degrees.
+func degrees(rad float64) float64 { return rad * 180 / math.Pi }
@@ -0,0 +1,129 @@
+package unit_test
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/dnesting/unit"
+)
+
+func approxComplex(a, b unit.Complex) bool {
+	return cmplx.Abs(a.C-b.C) < 1e-9 && a.U.Equal(b.U)
+}
+
+func TestComplex(t *testing.T) {
+	ohm := unit.Primitive("Ω")
+	kohm := unit.Derive("kΩ", ohm(1000))
+	a := unit.Primitive("A")
+
+	z := ohm.Complex(50 + 30i)
+	if r, ok := z.Add(kohm.Complex(0.01 - 0.02i)); !ok || !approxComplex(r, ohm.Complex(60+10i)) {
+		t.Errorf("50+j30 Ω + 10-j20 Ω should be 60+j10 Ω, got %v (ok=%v)", r, ok)
+	}
+	if r, ok := z.Sub(ohm.Complex(50 + 30i)); !ok || !approxComplex(r, ohm.Complex(0)) {
+		t.Errorf("z - z should be 0 Ω, got %v (ok=%v)", r, ok)
+	}
+	if r, ok := z.Add(a.Complex(1)); ok {
+		t.Errorf("adding Ω and A should fail, got %v", r)
+	}
+	if r := z.Mul(z.Conj()); !approxComplex(r, ohm.Pow(2).Complex(3400)) {
+		t.Errorf("z z* should be 3400 Ω^2, got %v", r)
+	}
+	if r := z.Div(ohm.Complex(2i)); !approxComplex(r, unit.Complex{C: 15 - 25i}) {
+		t.Errorf("z / j2 Ω should be 15-j25, got %v", r)
+	}
+	if r := z.MulV(a(2)); !approxComplex(r, ohm.Mul(a).Complex(100+60i)) {
+		t.Errorf("z × 2 A should be 100+j60 Ω A, got %v", r)
+	}
+	if r := ohm.Complex(2i).Recip(); !approxComplex(r, unit.Scalar(1).Div(ohm).Complex(-0.5i)) {
+		t.Errorf("1/(j2 Ω) should be -j0.5 /Ω, got %v", r)
+	}
+	if r := z.Abs(); !r.Approx(ohm(math.Sqrt(3400)), 1e-9) {
+		t.Errorf("|z| should be %g Ω, got %v", math.Sqrt(3400), r)
+	}
+	if r := z.Phase(); math.Abs(r-math.Atan2(30, 50)) > 1e-9 {
+		t.Errorf("phase of z should be %g, got %g", math.Atan2(30, 50), r)
+	}
+	if r := unit.Polar(ohm(2), math.Pi/2); !approxComplex(r, ohm.Complex(2i)) {
+		t.Errorf("2∠90° Ω should be j2 Ω, got %v", r)
+	}
+
+	if r, remain := kohm.Complex(1 + 2i).Convert(ohm); !remain.Empty() || !approxComplex(r, ohm.Complex(1000+2000i)) {
+		t.Errorf("1+j2 kΩ should convert to 1000+j2000 Ω, got %v (remain=%q)", r, remain)
+	}
+	if r, remain := z.Convert(a); remain.Empty() {
+		t.Errorf("Ω should not convert to A, got %v", r)
+	}
+	dbo := unit.Logarithmic("dBΩ", ohm(1), 1, false)
+	if r, remain := z.Convert(dbo); remain.Empty() || math.IsInf(real(r.C), 0) {
+		t.Errorf("Ω should not convert to dBΩ, got %v (remain=%q)", r, remain)
+	}
+}
+
+func TestFormatComplex(t *testing.T) {
+	ohm := unit.Primitive("Ω")
+	polar := unit.NewFormatter(unit.WithPolar(), unit.WithFmt("%.3g"))
+
+	for _, x := range []struct {
+		c              unit.Complex
+		rect, polarStr string
+	}{
+		{ohm.Complex(50 + 30i), "50+j30 Ω", "58.3∠31° Ω"},
+		{ohm.Complex(50 - 30i), "50-j30 Ω", "58.3∠-31° Ω"},
+		{unit.Complex{C: -2}, "-2+j0", "2∠180°"},
+	} {
+		if got := x.c.String(); got != x.rect {
+			t.Errorf("expected %q, got %q", x.rect, got)
+		}
+		if got := polar.FormatComplex(x.c); got != x.polarStr {
+			t.Errorf("expected %q, got %q", x.polarStr, got)
+		}
+	}
+}
+
+func TestParseComplex(t *testing.T) {
+	var r unit.Registry
+	ohm := r.Primitive("Ω")
+
+	for _, x := range []struct {
+		str      string
+		expected unit.Complex
+		err      bool
+	}{
+		{"50+j30 Ω", ohm.Complex(50 + 30i), false},
+		{"50-j30 Ω", ohm.Complex(50 - 30i), false},
+		{"2∠90° Ω", ohm.Complex(2i), false},
+		{"2∠-90 Ω", ohm.Complex(-2i), false},
+		{"50 Ω", ohm.Complex(50), false},
+		{"-1.5e2+j0.5", unit.Complex{C: -150 + 0.5i}, false},
+		{"50+j Ω", unit.Complex{}, true},
+		{"2∠ Ω", unit.Complex{}, true},
+		{"Ω", unit.Complex{}, true},
+	} {
+		c, err := unit.ParseComplex(x.str, &r, true)
+		if x.err {
+			if err == nil {
+				t.Errorf("ParseComplex(%q) should fail, got %v", x.str, c)
+			}
+			continue
+		}
+		if err != nil || !approxComplex(c, x.expected) {
+			t.Errorf("ParseComplex(%q) should give %v, got %v (err=%v)", x.str, x.expected, c, err)
+		}
+	}
+}
+
+func ExampleComplex() {
+	ohm := unit.Primitive("Ω")
+	v := unit.Primitive("V")
+
+	z := ohm.Complex(50 + 30i)
+	i := v.Complex(10).Div(z)
+	fmt.Println(z)
+	fmt.Println(unit.NewFormatter(unit.WithPolar(), unit.WithFmt("%.3g")).FormatComplex(i))
+	// Output:
+	// 50+j30 Ω
+	// 0.171∠-31° V/Ω
+}
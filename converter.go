@@ -0,0 +1,55 @@
+package unit
+
+// Converter converts scalars from one set of units to another, with the
+// conversion factor (and offset, for affine units) resolved once, when
+// the Converter is created.  A Converter is immutable and may be used by
+// many goroutines at once.
+type Converter struct {
+	scale, shift float64
+	fn           func(x float64) float64 // for nonlinear conversions
+}
+
+// NewConverter returns a Converter from the units of from to the units of
+// to, such that Convert(x) gives the scalar of from(x) converted to to.
+// Returns a *ConversionError if the units are not conformable.
+func NewConverter(from, to Maker) (Converter, error) {
+	f := from(1)
+	fu, tu := f.U, to.Units()
+	if la, lw := fu.logarithmic(), tu.logarithmic(); la != nil || lw != nil {
+		if _, ok := f.convertLog(la, tu, lw); !ok {
+			return Converter{}, newConversionError(fu, tu)
+		}
+		k := f.S
+		return Converter{fn: func(x float64) float64 {
+			r, _ := Value{S: x * k, U: fu}.convertLog(la, tu, lw)
+			return r.S
+		}}, nil
+	}
+	d := fu.dims().div(tu.dims())
+	if len(d.t) > 0 || !kindsAgree(fu, tu) {
+		return Converter{}, newConversionError(fu, tu)
+	}
+	off, to0 := offsets(fu, tu)
+	return Converter{scale: f.S * d.f, shift: off*d.f - to0}, nil
+}
+
+// Convert returns x converted.
+func (c Converter) Convert(x float64) float64 {
+	if c.fn != nil {
+		return c.fn(x)
+	}
+	return x*c.scale + c.shift
+}
+
+// ConvertSlice converts each element of xs in place.
+func (c Converter) ConvertSlice(xs []float64) {
+	if c.fn != nil {
+		for i, x := range xs {
+			xs[i] = c.fn(x)
+		}
+		return
+	}
+	for i, x := range xs {
+		xs[i] = x*c.scale + c.shift
+	}
+}
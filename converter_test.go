@@ -0,0 +1,85 @@
+package unit_test
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/dnesting/unit"
+)
+
+func TestConverter(t *testing.T) {
+	m := unit.Primitive("m")
+	km := unit.Derive("km", m(1000))
+	s := unit.Primitive("s")
+	h := unit.Derive("h", s(3600))
+	k := unit.Primitive("K")
+	c := unit.Affine("°C", unit.Derive("Δ°C", k), 273.15)
+	f := unit.Affine("°F", unit.Derive("Δ°F", k(5.0/9)), 459.67)
+	w := unit.Primitive("W")
+	dBm := unit.Logarithmic("dBm", w(0.001), 1, false)
+
+	for _, x := range []struct {
+		from, to unit.Maker
+		in, out  float64
+	}{
+		{km.Div(h), m.Div(s), 36, 10},
+		{m, m, 5, 5},
+		{c, f, 100, 212},
+		{f, c, -40, -40},
+		{dBm, w, 30, 1},
+		{w, dBm, 0.01, 10},
+		{unit.Scalar(2).Mul(km), m, 1, 2000},
+	} {
+		conv, err := unit.NewConverter(x.from, x.to)
+		if err != nil {
+			t.Errorf("NewConverter(%v, %v): %v", x.from, x.to, err)
+			continue
+		}
+		if got := conv.Convert(x.in); math.Abs(got-x.out) > 1e-9 {
+			t.Errorf("converting %g from %v to %v should give %g, got %g", x.in, x.from, x.to, x.out, got)
+		}
+		xs := []float64{x.in, x.in}
+		conv.ConvertSlice(xs)
+		if math.Abs(xs[0]-x.out) > 1e-9 || xs[0] != xs[1] {
+			t.Errorf("converting [%g %g] from %v to %v should give %g, got %v", x.in, x.in, x.from, x.to, x.out, xs)
+		}
+	}
+
+	var ce *unit.ConversionError
+	if _, err := unit.NewConverter(m, s); !errors.As(err, &ce) {
+		t.Errorf("converting m to s should fail with a ConversionError, got %v", err)
+	}
+	if _, err := unit.NewConverter(dBm, s); !errors.As(err, &ce) {
+		t.Errorf("converting dBm to s should fail with a ConversionError, got %v", err)
+	}
+}
+
+func TestConverterConcurrent(t *testing.T) {
+	m := unit.Primitive("m")
+	km := unit.Derive("km", m(1000))
+	conv, err := unit.NewConverter(km, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			xs := make([]float64, 1000)
+			for i := range xs {
+				xs[i] = float64(g)
+			}
+			conv.ConvertSlice(xs)
+			for _, x := range xs {
+				if x != float64(g)*1000 {
+					t.Errorf("expected %g, got %g", float64(g)*1000, x)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
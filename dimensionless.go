@@ -0,0 +1,68 @@
+package unit
+
+import "math/big"
+
+// Dimensionless creates a Maker associated with a new primitive unit named
+// symbol, for quantities such as plane and solid angles that are ratios of
+// like quantities.  Although dimensionless, such units are kept through
+// arithmetic and conversions like any other primitive unit, so that "2π rad"
+// is distinguishable from 6.28 and "rad/s" from "Hz".  Use
+// Value.DropDimensionless to remove them explicitly.
+func Dimensionless(symbol string) Maker {
+	u := newUnit(symbol, Unity)
+	u.dimensionless = true
+	return u.Make
+}
+
+// IsDimensionless returns true if u is non-nil and reduces only to
+// dimensionless units (see Dimensionless), such as the degree.
+func IsDimensionless(u Unit) bool {
+	if u == nil {
+		return false
+	}
+	d := unitDims(u)
+	if len(d.t) == 0 {
+		return false
+	}
+	for _, t := range d.t {
+		if p, ok := t.u.(*unitType); !ok || !p.dimensionless {
+			return false
+		}
+	}
+	return true
+}
+
+// DropDimensionless returns a with any dimensionless units (see
+// Dimensionless) removed, and its scalar multiplied by their value in the
+// primitive dimensionless units, so that "90 °" becomes 1.5708 and
+// "10 rad/s" becomes "10 /s".
+func (a Value) DropDimensionless() Value {
+	f := 1.0
+	r := big.NewRat(1, 1)
+	drop := func(l []Unit, inv bool) (keep []Unit) {
+		for _, u := range l {
+			if !IsDimensionless(u) {
+				keep = append(keep, u)
+				continue
+			}
+			d := unitDims(u)
+			if inv {
+				f /= d.f
+				r = quoRat(r, d.r)
+			} else {
+				f *= d.f
+				r = mulRat(r, d.r)
+			}
+		}
+		return
+	}
+	u := Units{N: drop(a.U.N, false), D: drop(a.U.D, true)}
+	if len(u.N) == len(a.U.N) && len(u.D) == len(a.U.D) {
+		return a
+	}
+	v := Value{S: a.S * f, U: u}
+	if a.R != nil {
+		v = v.withRat(mulRat(a.R, r))
+	}
+	return v
+}
@@ -0,0 +1,65 @@
+package unit_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/dnesting/unit"
+)
+
+func TestDimensionless(t *testing.T) {
+	rad := unit.Dimensionless("rad")
+	deg := unit.Derive("°", rad(math.Pi/180))
+	s := unit.Primitive("s")
+	hz := unit.Derive("Hz", unit.Scalar(1).Div(s))
+
+	if !unit.IsDimensionless(rad.Unit()) || !unit.IsDimensionless(deg.Unit()) {
+		t.Errorf("rad and ° should be dimensionless")
+	}
+	if unit.IsDimensionless(s.Unit()) || unit.IsDimensionless(hz.Unit()) {
+		t.Errorf("s and Hz should not be dimensionless")
+	}
+
+	if rad(2*math.Pi).Approx(unit.Value{S: 2 * math.Pi}, 1e-9) {
+		t.Errorf("2π rad should not be comparable to a bare scalar")
+	}
+	if _, remain := rad.Div(s)(1).Convert(hz); remain.Empty() {
+		t.Errorf("rad/s should not convert to Hz")
+	}
+	if r := rad(2).Mul(rad(3)); !r.Equal(rad.Pow(2)(6)) {
+		t.Errorf("rad × rad should be 6 rad^2, got %v", r)
+	}
+	if r := rad(6).Div(rad(3)); !r.Equal(unit.Value{S: 2}) {
+		t.Errorf("rad / rad should be 2, got %v", r)
+	}
+	if r, remain := deg(180).Convert(rad); !remain.Empty() || math.Abs(r.S-math.Pi) > 1e-12 {
+		t.Errorf("180 ° should be π rad, got %v (remain %q)", r, remain)
+	}
+
+	for _, x := range []struct {
+		v        unit.Value
+		expected unit.Value
+	}{
+		{rad(2), unit.Value{S: 2}},
+		{deg(90), unit.Value{S: math.Pi / 2}},
+		{rad.Div(s)(10), unit.Scalar(1).Div(s)(10)},
+		{unit.Scalar(1).Div(deg)(1), unit.Value{S: 180 / math.Pi}},
+		{s(3), s(3)},
+	} {
+		if r := x.v.DropDimensionless(); !r.Approx(x.expected, 1e-12) || !r.U.Equal(x.expected.U) {
+			t.Errorf("%v without dimensionless units should be %v, got %v", x.v, x.expected, r)
+		}
+	}
+}
+
+func ExampleValue_DropDimensionless() {
+	rad := unit.Dimensionless("rad")
+	s := unit.Primitive("s")
+	omega := rad.Div(s)(10)
+	fmt.Println(omega)
+	fmt.Println(omega.DropDimensionless())
+	// Output:
+	// 10 rad/s
+	// 10 /s
+}
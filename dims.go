@@ -0,0 +1,204 @@
+package unit
+
+import (
+	"math/big"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// dims is the canonical form of a unit or Units: a scalar factor and the
+// exponent of each primitive unit it reduces to.  Two Units conform to each
+// other if their dims have the same terms, and the ratio of their factors
+// is the conversion factor between them.
+//
+// Named units compute their dims once, when they are created, so that
+// comparisons, conversions and Reduce don't need to walk derivation
+// chains.  Dims for Units combine the cached dims of their parts.
+type dims struct {
+	f   float64
+	t   []term   // sorted by symbol, with no zero exponents
+	r   *big.Rat // f, exactly, if known; only set for individual units
+	red *Units   // t as Units, if memoized; only set for individual units
+}
+
+// dimensioned is implemented by the unit types in this package, which
+// cache their dims.
+type dimensioned interface {
+	dims() dims
+}
+
+// maxForeignDims bounds the number of units cached in foreignDims.  The
+// dims of units beyond it are computed each time they are needed.
+const maxForeignDims = 1024
+
+// foreignDims caches the dims of Unit implementations from outside this
+// package, keyed by the Unit itself, for those that are hashable.  Its
+// entries are never removed, so nForeignDims counts them to keep it
+// bounded.
+var (
+	foreignDims  sync.Map
+	nForeignDims int64
+)
+
+// unitDims returns the dims for u, computing them from u.Deriv() if u
+// does not cache them.
+func unitDims(u Unit) dims {
+	if d, ok := u.(dimensioned); ok {
+		return d.dims()
+	}
+	if !hashable(reflect.TypeOf(u)) {
+		return derivDims(u)
+	}
+	if d, ok := foreignDims.Load(u); ok {
+		return d.(dims)
+	}
+	d := derivDims(u).memo()
+	if atomic.LoadInt64(&nForeignDims) >= maxForeignDims {
+		return d
+	}
+	if got, loaded := foreignDims.LoadOrStore(u, d); loaded {
+		return got.(dims)
+	}
+	atomic.AddInt64(&nForeignDims, 1)
+	return d
+}
+
+// hashable returns true if values of type t can be used as map keys
+// without panicking.  Comparable types holding interfaces are not, since
+// the values in them might not be comparable.
+func hashable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface:
+		return false
+	case reflect.Array:
+		return hashable(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !hashable(t.Field(i).Type) {
+				return false
+			}
+		}
+		return true
+	}
+	return t.Comparable()
+}
+
+// derivDims computes the dims for u from its derivation.
+func derivDims(u Unit) dims {
+	if IsPrimitive(u) {
+		return primitiveDims(u)
+	}
+	v := u.Deriv()
+	d := v.U.dims()
+	d.f *= v.S
+	d.r = mulRat(v.exact(), v.U.exactFactor())
+	return d
+}
+
+// primitiveDims returns the dims for the primitive unit u.
+func primitiveDims(u Unit) dims {
+	return dims{f: 1, t: []term{{u, rat{1, 1}}}, r: big.NewRat(1, 1), red: &Units{N: []Unit{u}}}
+}
+
+// memo returns d with its reduced units memoized, for caching as the dims
+// of an individual unit.
+func (d dims) memo() dims {
+	us := fromTerms(d.t)
+	d.red = &us
+	return d
+}
+
+// dims combines the dims of each unit in us.
+func (us Units) dims() dims {
+	if len(us.N) == 1 && len(us.D) == 0 {
+		return unitDims(us.N[0])
+	}
+	d := dims{f: 1}
+	for _, u := range us.N {
+		if u != nil {
+			d.add(unitDims(u), rat{1, 1})
+		}
+	}
+	for _, u := range us.D {
+		if u != nil {
+			d.add(unitDims(u), rat{-1, 1})
+		}
+	}
+	d.normalize()
+	return d
+}
+
+// add multiplies d by o raised to the power p.  The result must be
+// normalized before use.
+func (d *dims) add(o dims, p rat) {
+	if p.isInt() && p.n == 1 {
+		d.f *= o.f
+	} else if p.isInt() && p.n == -1 {
+		d.f /= o.f
+	} else {
+		d.f *= powf(o.f, p)
+	}
+outer:
+	for _, ot := range o.t {
+		e := ot.e.mul(p)
+		for i := range d.t {
+			if d.t[i].u.Symbol() == ot.u.Symbol() {
+				d.t[i].e = d.t[i].e.add(e)
+				continue outer
+			}
+		}
+		d.t = append(d.t, term{ot.u, e})
+	}
+}
+
+// normalize removes terms with zero exponents and sorts the remainder.
+func (d *dims) normalize() {
+	w := 0
+	for _, t := range d.t {
+		if t.e.n != 0 {
+			d.t[w] = t
+			w++
+		}
+	}
+	d.t = d.t[:w]
+	sort.Slice(d.t, func(i, j int) bool { return d.t[i].u.Symbol() < d.t[j].u.Symbol() })
+}
+
+// pow returns d raised to the power p.
+func (d dims) pow(p rat) dims {
+	r := dims{f: 1}
+	r.add(d, p)
+	r.normalize()
+	return r
+}
+
+// div returns d divided by o.
+func (d dims) div(o dims) dims {
+	r := dims{f: d.f, t: append([]term(nil), d.t...)}
+	r.add(o, rat{-1, 1})
+	r.normalize()
+	return r
+}
+
+// conforms returns true if d and o reduce to the same primitive units.
+func (d dims) conforms(o dims) bool {
+	if len(d.t) != len(o.t) {
+		return false
+	}
+	for i := range d.t {
+		if d.t[i].e != o.t[i].e || d.t[i].u.Symbol() != o.t[i].u.Symbol() {
+			return false
+		}
+	}
+	return true
+}
+
+// units returns the primitive units described by d.
+func (d dims) units() Units {
+	if d.red != nil {
+		return *d.red
+	}
+	return fromTerms(d.t)
+}
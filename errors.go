@@ -0,0 +1,130 @@
+package unit
+
+import "fmt"
+
+// ConversionError describes a failure to convert between units that do
+// not reduce to the same primitive units, or that describe different kinds
+// of quantity (see Kind).  It wraps Incomparable, so
+// errors.Is(err, Incomparable) holds for any ConversionError.
+type ConversionError struct {
+	From, To         Units // the units converted from and to
+	FromDims, ToDims Units // From and To, reduced to primitive units
+	Mismatch         Units // FromDims/ToDims, the units preventing conversion
+	FromKind, ToKind *Kind // the kinds of From and To, if any
+}
+
+func newConversionError(from, to Units) *ConversionError {
+	df, dt := from.dims(), to.dims()
+	return &ConversionError{
+		From:     from,
+		To:       to,
+		FromDims: df.units(),
+		ToDims:   dt.units(),
+		Mismatch: df.div(dt).units(),
+		FromKind: from.Kind(),
+		ToKind:   to.Kind(),
+	}
+}
+
+// kinds returns true if the conversion failed only because the kinds of
+// From and To differ.
+func (e *ConversionError) kinds() bool {
+	return e.Mismatch.Empty() && e.FromKind != nil && e.ToKind != nil
+}
+
+func (e *ConversionError) Error() string {
+	if e.kinds() {
+		return fmt.Sprintf("%v: expected %q (%v), got %q (%v)", Incomparable, e.To, e.ToKind, e.From, e.FromKind)
+	}
+	return fmt.Sprintf("%v: expected %q (%v), got %q (%v)", Incomparable, e.To, e.ToDims, e.From, e.FromDims)
+}
+
+func (e *ConversionError) Unwrap() error { return Incomparable }
+
+// Describe returns a message of the form "expected length, got time",
+// where name returns a description of a set of primitive units, such as
+// "length" for m.  If the units differ only in kind, the kinds are named
+// instead.
+func (e *ConversionError) Describe(name func(dims Units) string) string {
+	if e.kinds() {
+		return fmt.Sprintf("expected %v, got %v", e.ToKind, e.FromKind)
+	}
+	return fmt.Sprintf("expected %s, got %s", name(e.ToDims), name(e.FromDims))
+}
+
+// ConvertErr converts a into the units of wanted, as described in Convert.
+// If the units are not conformable, it returns a *ConversionError.
+func (a Value) ConvertErr(wanted Maker) (Value, error) {
+	r, remain := a.Convert(wanted)
+	if !remain.Empty() {
+		return Value{}, newConversionError(a.U, wanted.Units())
+	}
+	return r, nil
+}
+
+// AddErr adds a and b, as described in Add.  If b's units cannot be
+// converted into a's, it returns a *ConversionError.  Other failures,
+// such as adding two absolute readings, return an error wrapping
+// Incomparable.
+func (a Value) AddErr(b Value) (Value, error) {
+	if r, ok := a.Add(b); ok {
+		return r, nil
+	}
+	return Value{}, a.sumError("add", b)
+}
+
+// SubErr subtracts b from a, as described in Sub.  If b's units cannot be
+// converted into a's, it returns a *ConversionError.  Other failures,
+// such as subtracting an absolute reading from a difference, return an
+// error wrapping Incomparable.
+func (a Value) SubErr(b Value) (Value, error) {
+	if r, ok := a.Sub(b); ok {
+		return r, nil
+	}
+	return Value{}, a.sumError("subtract", b)
+}
+
+// MulErr multiplies a and b, as described in Mul.  If either is an
+// absolute reading on an affine scale (see Affine), it returns an error
+// wrapping Absolute instead of treating the reading as a difference.
+func (a Value) MulErr(b Qualified) (Value, error) {
+	if err := absoluteError("multiply", a, b); err != nil {
+		return Value{}, err
+	}
+	return a.Mul(b), nil
+}
+
+// DivErr divides a by b, as described in Div.  If either is an absolute
+// reading on an affine scale (see Affine), it returns an error wrapping
+// Absolute instead of treating the reading as a difference.
+func (a Value) DivErr(b Qualified) (Value, error) {
+	if err := absoluteError("divide", a, b); err != nil {
+		return Value{}, err
+	}
+	return a.Div(b), nil
+}
+
+// PowErr raises a to the power of n, as described in Pow.  If a is an
+// absolute reading on an affine scale (see Affine) and n is not 1, it
+// returns an error wrapping Absolute.
+func (a Value) PowErr(n int) (Value, error) {
+	if n != 1 && a.IsAbsolute() {
+		return Value{}, fmt.Errorf("%w: cannot raise %q to a power", Absolute, a)
+	}
+	return a.Pow(n), nil
+}
+
+// absoluteError returns an error if a or b is an absolute reading.
+func absoluteError(op string, a Value, b Qualified) error {
+	if a.IsAbsolute() || b.Units().absolute() != nil {
+		return fmt.Errorf("%w: cannot %s %q and %q", Absolute, op, a, FromQualified(b))
+	}
+	return nil
+}
+
+func (a Value) sumError(op string, b Value) error {
+	if !a.U.Equiv(b.U) || !kindsAgree(a.U, b.U) {
+		return newConversionError(b.U, a.U)
+	}
+	return fmt.Errorf("%w: cannot %s %q and %q", Incomparable, op, a, b)
+}
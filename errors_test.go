@@ -0,0 +1,95 @@
+package unit_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dnesting/unit"
+)
+
+func TestConversionError(t *testing.T) {
+	m := unit.Primitive("m")
+	km := unit.Derive("km", m(1000))
+	s := unit.Primitive("s")
+	h := unit.Derive("h", s(3600))
+
+	_, err := h(1).ConvertErr(km)
+	var ce *unit.ConversionError
+	if !errors.As(err, &ce) {
+		t.Fatalf("converting h to km should give a ConversionError, got %v", err)
+	}
+	if !errors.Is(err, unit.Incomparable) {
+		t.Errorf("%v should wrap Incomparable", err)
+	}
+	if !ce.From.Equal(h.Units()) || !ce.To.Equal(km.Units()) {
+		t.Errorf("expected conversion from h to km, got %q to %q", ce.From, ce.To)
+	}
+	if !ce.FromDims.Equal(s.Units()) || !ce.ToDims.Equal(m.Units()) || !ce.Mismatch.Equal(s.Div(m).Units()) {
+		t.Errorf("expected dimensions s, m and mismatch s/m, got %q, %q and %q", ce.FromDims, ce.ToDims, ce.Mismatch)
+	}
+	names := func(u unit.Units) string {
+		switch {
+		case u.Equal(m.Units()):
+			return "length"
+		case u.Equal(s.Units()):
+			return "time"
+		}
+		return u.String()
+	}
+	if e, got := "expected length, got time", ce.Describe(names); got != e {
+		t.Errorf("expected %q, got %q", e, got)
+	}
+
+	if r, err := km(1).ConvertErr(m); err != nil || !r.Equal(m(1000)) {
+		t.Errorf("1 km should convert to 1000 m, got %v (err=%v)", r, err)
+	}
+}
+
+func TestAddSubErr(t *testing.T) {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+	c := unit.Affine("°C", unit.Derive("Δ°C", unit.Primitive("K")), 273.15)
+
+	if r, err := m(1).AddErr(m(2)); err != nil || !r.Equal(m(3)) {
+		t.Errorf("1 m + 2 m should be 3 m, got %v (err=%v)", r, err)
+	}
+	if r, err := m(1).SubErr(m(2)); err != nil || !r.Equal(m(-1)) {
+		t.Errorf("1 m - 2 m should be -1 m, got %v (err=%v)", r, err)
+	}
+
+	var ce *unit.ConversionError
+	if _, err := m(1).AddErr(s(2)); !errors.As(err, &ce) || !ce.From.Equal(s.Units()) || !ce.To.Equal(m.Units()) {
+		t.Errorf("1 m + 2 s should fail converting s to m, got %v", err)
+	}
+	if _, err := m(1).SubErr(s(2)); !errors.As(err, &ce) {
+		t.Errorf("1 m - 2 s should fail with a ConversionError, got %v", err)
+	}
+	_, err := c(1).AddErr(c(2))
+	if !errors.Is(err, unit.Incomparable) || errors.As(err, &ce) {
+		t.Errorf("adding two absolute temperatures should fail with Incomparable, got %v", err)
+	}
+	if _, err := m(1).Cmp(s(1)); !errors.As(err, &ce) {
+		t.Errorf("comparing m and s should fail with a ConversionError, got %v", err)
+	}
+}
+
+func TestMustConvertPanics(t *testing.T) {
+	defer func() {
+		err, _ := recover().(error)
+		var ce *unit.ConversionError
+		if !errors.As(err, &ce) {
+			t.Errorf("MustConvert should panic with a ConversionError, got %v", err)
+		}
+	}()
+	unit.MustConvert(unit.Primitive("m")(1), unit.Primitive("s"))
+}
+
+func ExampleConversionError() {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+
+	_, err := m(1).AddErr(s(2))
+	fmt.Println(err)
+	// Output: unit mismatch: expected "m" (m), got "s" (s)
+}
@@ -0,0 +1,150 @@
+package unit
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// Values may optionally carry an exact rational scalar in their R field.
+// Operations on such values keep the result exact for as long as every
+// conversion factor involved is known exactly, falling back to float64
+// otherwise.  Conversion factors are known exactly when the values a unit
+// is derived from are exact: either carrying an R of their own, or
+// written as decimal float64 literals, which are taken at face value so
+// that 0.0254 means exactly 254/10000.
+
+// decimal returns f as a rational number, taking its shortest decimal
+// representation at face value.  Returns nil if f is infinite or NaN.
+func decimal(f float64) *big.Rat {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil
+	}
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	return r
+}
+
+func mulRat(a, b *big.Rat) *big.Rat {
+	if a == nil || b == nil {
+		return nil
+	}
+	return new(big.Rat).Mul(a, b)
+}
+
+func quoRat(a, b *big.Rat) *big.Rat {
+	if a == nil || b == nil || b.Sign() == 0 {
+		return nil
+	}
+	return new(big.Rat).Quo(a, b)
+}
+
+func addRat(a, b *big.Rat) *big.Rat {
+	if a == nil || b == nil {
+		return nil
+	}
+	return new(big.Rat).Add(a, b)
+}
+
+func subRat(a, b *big.Rat) *big.Rat {
+	if a == nil || b == nil {
+		return nil
+	}
+	return new(big.Rat).Sub(a, b)
+}
+
+// ratPow raises a to the integer power n.
+func ratPow(a *big.Rat, n int) *big.Rat {
+	if a == nil {
+		return nil
+	}
+	if n < 0 {
+		return quoRat(big.NewRat(1, 1), ratPow(a, -n))
+	}
+	num := new(big.Int).Exp(a.Num(), big.NewInt(int64(n)), nil)
+	den := new(big.Int).Exp(a.Denom(), big.NewInt(int64(n)), nil)
+	return new(big.Rat).SetFrac(num, den)
+}
+
+// ratOf returns the exact scalar carried by q, or nil if it carries none.
+func ratOf(q Qualified) *big.Rat {
+	switch v := q.(type) {
+	case Value:
+		return v.R
+	case *Value:
+		return v.R
+	}
+	return nil
+}
+
+// exact returns v's exact scalar, or v.S taken at face value if it has
+// none.
+func (v Value) exact() *big.Rat {
+	if v.R != nil {
+		return v.R
+	}
+	return decimal(v.S)
+}
+
+// withRat returns v with its exact scalar set to r, and its float64 scalar
+// set to the nearest approximation of r.  If r is nil, v keeps its float64
+// scalar and loses any exact scalar.
+func (v Value) withRat(r *big.Rat) Value {
+	v.R = r
+	if r != nil {
+		v.S, _ = r.Float64()
+	}
+	return v
+}
+
+// Exact returns v carrying an exact rational scalar.  If v does not
+// already have one, its float64 scalar is taken at face value, so that
+// 0.1 becomes exactly 1/10.
+func (v Value) Exact() Value {
+	if v.R != nil {
+		return v
+	}
+	return v.withRat(decimal(v.S))
+}
+
+// MakeRat creates a new qualified value of r in m's units, carrying r as
+// an exact scalar.  This is useful for deriving units whose factors can't
+// be written as decimals, such as Inch.MakeRat(big.NewRat(1, 6)).  The
+// value of r must not be modified afterward.
+func (m Maker) MakeRat(r *big.Rat) Value {
+	v := m(1)
+	return v.withRat(mulRat(v.exact(), r))
+}
+
+// exactFactor returns the exact factor that reduces us to primitive
+// units, or nil if it is not known exactly.
+func (us Units) exactFactor() *big.Rat {
+	r := big.NewRat(1, 1)
+	for _, u := range us.N {
+		if u != nil {
+			if ur := unitDims(u).r; ur != nil {
+				r.Mul(r, ur)
+			} else {
+				return nil
+			}
+		}
+	}
+	for _, u := range us.D {
+		if u != nil {
+			if ur := unitDims(u).r; ur != nil && ur.Sign() != 0 {
+				r.Quo(r, ur)
+			} else {
+				return nil
+			}
+		}
+	}
+	return r
+}
+
+// convertRat returns a's scalar converted exactly into the conforming
+// units wu, or nil if the conversion is not exact.
+func (a Value) convertRat(wu Units) *big.Rat {
+	from, to := offsets(a.U, wu)
+	r := addRat(a.exact(), decimal(from))
+	r = mulRat(r, quoRat(a.U.exactFactor(), wu.exactFactor()))
+	return subRat(r, decimal(to))
+}
@@ -0,0 +1,89 @@
+package unit_test
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/dnesting/unit"
+)
+
+func TestExact(t *testing.T) {
+	m := unit.Primitive("m")
+	in := unit.Derive("in", m(0.0254))
+	ft := unit.Derive("ft", in(12))
+	pica := unit.Derive("P", in.MakeRat(big.NewRat(1, 6)))
+	third := unit.Derive("third", in(1.0/3))
+
+	for _, c := range []struct {
+		desc     string
+		a        unit.Value
+		to       unit.Maker
+		expected *big.Rat
+	}{
+		{"decimal", ft(1).Exact(), m, big.NewRat(3048, 10000)},
+		{"rational", pica(1).Exact(), in, big.NewRat(1, 6)},
+		{"reverse", m(1).Exact(), in, big.NewRat(10000, 254)},
+		{"make", m.MakeRat(big.NewRat(1, 3)), ft, big.NewRat(10000, 3*3048)},
+		{"float taken at face value", third(1).Exact(), in, big.NewRat(3333333333333333, 1e16)},
+	} {
+		t.Run(c.desc, func(t *testing.T) {
+			r, remain := c.a.Convert(c.to)
+			if !remain.Empty() || r.R == nil || r.R.Cmp(c.expected) != 0 {
+				t.Errorf("%q.Convert(%v) should give exactly %v, got %v (%q)", c.a, c.to, c.expected, r.R, r)
+			}
+			if f, _ := c.expected.Float64(); r.S != f {
+				t.Errorf("%q.Convert(%v) should have S=%v, got %v", c.a, c.to, f, r.S)
+			}
+		})
+	}
+
+	a := in(1).Exact()
+	r := a.MulN(3).DivN(3).Mul(ft(2)).Div(ft(2)).Pow(2).Pow(-1).Recip()
+	if r.R == nil || r.R.Cmp(big.NewRat(1, 1)) != 0 {
+		t.Errorf("arithmetic should keep values exact, got %v (%q)", r.R, r)
+	}
+	if s, ok := a.Add(ft(1)); !ok || s.R == nil || s.R.Cmp(big.NewRat(13, 1)) != 0 {
+		t.Errorf("%q + 1 ft should be exactly 13 in, got %v (%q)", a, s.R, s)
+	}
+	if s, ok := ft(1).Sub(a); !ok || s.R == nil || s.R.Cmp(big.NewRat(11, 12)) != 0 {
+		t.Errorf("1 ft - %q should be exactly 11/12 ft, got %v (%q)", a, s.R, s)
+	}
+	if v := in(0.1).Exact().Reduce(); v.R == nil || v.R.Cmp(big.NewRat(254, 100000)) != 0 {
+		t.Errorf("0.1 in should reduce to exactly 0.00254 m, got %v", v.R)
+	}
+}
+
+func TestExactEqual(t *testing.T) {
+	m := unit.Primitive("m")
+	in := unit.Derive("in", m(0.0254))
+	ft := unit.Derive("ft", in(12))
+	mi := unit.Derive("mi", ft(5280))
+
+	a := mi(1).Exact()
+	b, _ := a.Convert(in)
+	b, _ = b.Convert(m)
+	b, _ = b.Convert(ft)
+	b, _ = b.Convert(mi)
+	if !a.Equal(b) || b.S != 1 {
+		t.Errorf("%q should round trip exactly, got %q", a, b)
+	}
+	if !mi(1).Equal(m.MakeRat(big.NewRat(201168, 125))) {
+		t.Errorf("1 mi should equal exactly 1609.344 m")
+	}
+	if mi(1).Exact().Equal(m(1609.3439999)) {
+		t.Errorf("1 mi should not equal 1609.3439999 m")
+	}
+}
+
+func ExampleValue_Exact() {
+	m := unit.Primitive("m")
+	in := unit.Derive("in", m(0.0254))
+	mi := unit.Derive("mi", in(63360))
+
+	f, _ := mi(1).Convert(m)
+	e, _ := mi(1).Exact().Convert(m)
+	fmt.Println(f.S, e.S, e.R)
+	// Output:
+	// 1609.3439999999998 1609.344 201168/125
+}
@@ -2,7 +2,10 @@ package unit
 
 import (
 	"fmt"
+	"math"
+	"math/cmplx"
 	"runtime/debug"
+	"strconv"
 	"strings"
 )
 
@@ -12,12 +15,17 @@ import (
 // be created with NewFormatter.
 type Formatter struct {
 	unitFn         func(u Unit) string
-	powerFn        func(u string, p int) string
+	powerFn        func(u string, p rat) string
 	valueFn        func(tmpl string, v float64) string
 	fracFn         func(num, denom string) string
 	negativePowers bool
 	valueFmt       string
 	noGapFor       []Units
+	conciseSigma   bool
+	polar          bool
+	longNames      bool
+	simplifier     *simplifier
+	registry       *Registry
 
 	beforeUnits   string
 	beforeUnitRow string
@@ -82,6 +90,10 @@ func WithNoGap() FormatOpt {
 	return func(f *Formatter) { f.beforeUnits = "" }
 }
 
+// WithNoGapFor eliminates the space between a value's scalar component
+// and its units, if they are the units of one of ms.  Units whose Info has
+// NoSpace set (see Info) in the formatter's registry (see WithRegistry)
+// are always written without one when written by their symbols.
 func WithNoGapFor(ms ...Maker) FormatOpt {
 	return func(f *Formatter) {
 		for _, m := range ms {
@@ -105,6 +117,64 @@ func WithFmt(tmp string) FormatOpt {
 	return func(f *Formatter) { f.valueFmt = tmp }
 }
 
+// WithConciseUncertainty formats uncertain values (see Uncertain) in the
+// concise notation "9.81(2) m/s^2", where the digits in parentheses give
+// the uncertainty in the last digits of the value.  By default, they are
+// formatted as "9.81 ± 0.02 m/s^2".
+func WithConciseUncertainty() FormatOpt {
+	return func(f *Formatter) { f.conciseSigma = true }
+}
+
+// WithPolar formats complex values (see Complex) in polar form, as in
+// "58.3∠31° Ω", with the phase angle in degrees.  By default, they are
+// formatted in rectangular form, as in "50+j30 Ω".
+func WithPolar() FormatOpt {
+	return func(f *Formatter) { f.polar = true }
+}
+
+// WithSimplify rewrites values into the most compact product of powers of
+// the units in set before formatting them, as described in Value.Simplify,
+// so that "1 kg m^2/s^2" is formatted as "1 J".
+func WithSimplify(set ...Maker) FormatOpt {
+	s := newSimplifier(set)
+	return func(f *Formatter) { f.simplifier = s }
+}
+
+// WithRegistry qualifies units that share a symbol with a different unit
+// in the same value by the name of the registry they're registered in, as
+// in "us:ft survey:ft", looking in r and its parents (see
+// Registry.QualifiedSymbol).  Units not registered in a named registry, or
+// formatted without this option, are followed by their derivations in
+// brackets instead, as in "ft[0.3048 m]".  The Info attached to units in r
+// (see Registry.Describe) is also used, as for Info.NoSpace.
+func WithRegistry(r *Registry) FormatOpt {
+	return func(f *Formatter) { f.registry = r }
+}
+
+// WithLongNames formats units by their long names in r or its parents
+// (see Registry.Describe), as in "9.8 metres per second^2", using the
+// plural of the last unit in the numerator unless the value is 1 or -1.
+// Prefixed units are named by their prefix's long name followed by the
+// unit's, as in "kilometres" (see Registry.DescribePrefix).  Units with no
+// long name are formatted by their symbols.  The registry is also used as
+// with WithRegistry.
+func WithLongNames(r *Registry) FormatOpt {
+	return func(f *Formatter) {
+		f.registry = r
+		f.longNames = true
+		f.unitSep = " "
+		f.fracFn = func(n, d string) string {
+			switch {
+			case d == "":
+				return n
+			case n == "":
+				return "per " + d
+			}
+			return n + " per " + d
+		}
+	}
+}
+
 // WithNoFraction specifies that the units should not be rendered as
 // a fraction.  Units in the denominator will be rendered with a negative
 // exponent instead.
@@ -120,16 +190,50 @@ func WithNoFraction() FormatOpt {
 	}
 }
 
+// WithDecimalPowers renders fractional exponents as decimals, as in
+// "Hz^0.5", instead of the default "Hz^(1/2)".  Exponents that cannot be
+// written exactly as a decimal, such as 1/3, are rendered as fractions.
+func WithDecimalPowers() FormatOpt {
+	return func(f *Formatter) { f.powerFn = decimalFormatPower }
+}
+
 // SUPERSCRIPT ZERO through SUPERSCRIPT NINE
 var supers = []rune("\u2070\u00B9\u00B2\u00B3\u2074\u2075\u2076\u2077\u2078\u2079")
 
 const superMinus = '\u207B'
 
-func formatUnicodePower(u string, pow int) string {
+// VULGAR FRACTION characters, for fractional exponents
+var vulgar = map[rat]rune{
+	{1, 2}: '½', {1, 3}: '⅓', {2, 3}: '⅔', {1, 4}: '¼', {3, 4}: '¾',
+	{1, 5}: '⅕', {2, 5}: '⅖', {3, 5}: '⅗', {4, 5}: '⅘', {1, 6}: '⅙',
+	{5, 6}: '⅚', {1, 8}: '⅛', {3, 8}: '⅜', {5, 8}: '⅝', {7, 8}: '⅞',
+}
+
+func fromVulgar(r rune) (rat, bool) {
+	for k, v := range vulgar {
+		if v == r {
+			return k, true
+		}
+	}
+	return rat{}, false
+}
+
+func formatUnicodePower(u string, p rat) string {
 	var minus bool
-	if pow == 1 {
+	if p == (rat{1, 1}) {
 		return u
 	}
+	if !p.isInt() {
+		abs, sign := p, ""
+		if abs.n < 0 {
+			abs.n, sign = -abs.n, string(superMinus)
+		}
+		if r, ok := vulgar[abs]; ok {
+			return u + sign + string(r)
+		}
+		return defaultFormatPower(u, p)
+	}
+	pow := p.n
 	if pow < 0 {
 		minus = true
 		pow *= -1
@@ -170,11 +274,14 @@ func WithUnicode() FormatOpt {
 // of the qualified value.  Enclosing <math> tags are not generated.
 func WithMathML() FormatOpt {
 	return func(f *Formatter) {
-		f.powerFn = func(u string, p int) string {
-			if p == 1 {
+		f.powerFn = func(u string, p rat) string {
+			if p == (rat{1, 1}) {
 				return fmt.Sprintf("<mi>%s</mi>", u)
 			}
-			return fmt.Sprintf("<msup><mi>%s</mi><mn>%d</mn></msup>", u, p)
+			if !p.isInt() {
+				return fmt.Sprintf("<msup><mi>%s</mi><mfrac><mn>%d</mn><mn>%d</mn></mfrac></msup>", u, p.n, p.d)
+			}
+			return fmt.Sprintf("<msup><mi>%s</mi><mn>%d</mn></msup>", u, p.n)
 		}
 		f.valueFn = func(tmp string, v float64) string {
 			return fmt.Sprintf("<mn>%s</mn>", fmt.Sprintf(tmp, v))
@@ -209,15 +316,39 @@ const (
 	DotOperator   = '\u22C5'
 	FractionSlash = '\u2044'
 	Nbsp          = '\u00A0'
+	EnDash        = '\u2013'
+	AngleSign     = '\u2220'
 )
 
 func defaultFormatValue(tmp string, v float64) string { return fmt.Sprintf(tmp, v) }
 func defaultFormatUnit(u Unit) string                 { return u.Symbol() }
-func defaultFormatPower(u string, p int) string {
-	if p == 1 {
+func defaultFormatPower(u string, p rat) string {
+	if p == (rat{1, 1}) {
 		return u
 	}
-	return fmt.Sprintf("%s^%d", u, p)
+	if !p.isInt() {
+		return fmt.Sprintf("%s^(%d/%d)", u, p.n, p.d)
+	}
+	return fmt.Sprintf("%s^%d", u, p.n)
+}
+
+// terminates returns true if p can be written exactly as a decimal.
+func (p rat) terminates() bool {
+	d := p.d
+	for d%2 == 0 {
+		d /= 2
+	}
+	for d%5 == 0 {
+		d /= 5
+	}
+	return d == 1
+}
+
+func decimalFormatPower(u string, p rat) string {
+	if p.isInt() || !p.terminates() {
+		return defaultFormatPower(u, p)
+	}
+	return u + "^" + strconv.FormatFloat(p.float(), 'f', -1, 64)
 }
 func defaultFormatFraction(n, d string) string {
 	if d != "" {
@@ -256,15 +387,63 @@ func (f *Formatter) Config(opts ...FormatOpt) {
 	}
 }
 
-func (f *Formatter) formatUnitList(us []Unit, mult int) (r []string) {
+// ambiguous returns the units in us that share a symbol with a different
+// unit in us, and so must be formatted with a disambiguated symbol (see
+// disambiguate).
+func ambiguous(us Units) (r []Unit) {
+	n := len(us.N) + len(us.D)
+	at := func(i int) Unit {
+		if i < len(us.N) {
+			return us.N[i]
+		}
+		return us.D[i-len(us.N)]
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if a, b := at(i), at(j); a != nil && b != nil && a.Symbol() == b.Symbol() && !a.Equal(b) {
+				r = append(r, a, b)
+			}
+		}
+	}
+	return
+}
+
+// disambiguate returns a symbol for u that distinguishes it from other
+// units with the same symbol: its symbol qualified with the registry it
+// belongs to (see WithRegistry), or failing that, its symbol followed by
+// its derivation in brackets, as in "ft[0.3048 m]".  Primitive units, which
+// have no derivation to show, keep their symbol.
+func (f *Formatter) disambiguate(u Unit) string {
+	if q := f.registry.QualifiedSymbol(u); q != u.Symbol() || IsPrimitive(u) {
+		return q
+	}
+	return fmt.Sprintf("%s[%v]", u.Symbol(), u.Deriv())
+}
+
+// formatUnitList formats the units us.  If plural is true, the last is
+// formatted by its plural long name when formatting long names.
+func (f *Formatter) formatUnitList(us []Unit, mult int, amb []Unit, plural bool) (r []string) {
 	if len(us) == 0 {
 		return nil
 	}
 	prev := us[0]
 	pow := 1
-	add := func() {
-		ustr := f.powerFn(f.unitFn(prev), pow*mult)
-		r = append(r, ustr)
+	add := func(last bool) {
+		u, p := prev, newRat(pow*mult, 1)
+		if root, ok := u.(*rootType); ok {
+			u, p = root.inner, newRat(pow*mult, root.den)
+		}
+		name := f.unitFn(u)
+		if f.longNames {
+			name = f.longName(u, last && plural)
+		}
+		for _, a := range amb {
+			if a.Equal(u) && name == u.Symbol() {
+				name = f.disambiguate(u)
+				break
+			}
+		}
+		r = append(r, f.powerFn(name, p))
 	}
 	for i := 1; i < len(us); i++ {
 		if us[i] == nil {
@@ -275,22 +454,37 @@ func (f *Formatter) formatUnitList(us []Unit, mult int) (r []string) {
 			continue
 		}
 		if prev != nil {
-			add()
+			add(false)
 		}
 		prev = us[i]
 	}
-	add()
+	add(true)
 	return
 }
 
 // FormatUnits formats the Units according the Formatter's configuration.
+//
+// If us is marked with a kind (see Value.As) whose units differ from us only
+// in name, the kind's units are formatted instead, so that "1/s" marked as
+// radioactive activity is formatted as "Bq".
 func (f *Formatter) FormatUnits(us Units) string {
+	return f.formatUnits(us, false)
+}
+
+// formatUnits formats us, using the plural long name of the last unit in
+// the numerator if plural is true and long names are being formatted.
+func (f *Formatter) formatUnits(us Units, plural bool) string {
+	us = us.preferred()
+	var amb []Unit
+	if len(us.N)+len(us.D) > 1 {
+		amb = ambiguous(us)
+	}
 	var sb strings.Builder
 	if us.N != nil || us.D != nil {
 		var num strings.Builder
 		var mult int = 1
 		if us.N != nil {
-			strs := f.formatUnitList(us.N, mult)
+			strs := f.formatUnitList(us.N, mult, amb, plural)
 			num.WriteString(f.beforeUnitRow)
 			num.WriteString(strings.Join(strs, f.unitSep))
 			num.WriteString(f.afterUnitRow)
@@ -300,7 +494,7 @@ func (f *Formatter) FormatUnits(us Units) string {
 			if f.negativePowers {
 				mult = -1
 			}
-			strs := f.formatUnitList(us.D, mult)
+			strs := f.formatUnitList(us.D, mult, amb, false)
 			denom.WriteString(f.beforeUnitRow)
 			denom.WriteString(strings.Join(strs, f.unitSep))
 			denom.WriteString(f.afterUnitRow)
@@ -322,6 +516,110 @@ func (f *Formatter) Format(v Value) string {
 	return f.Sprintf(f.valueFmt, v)
 }
 
+// FormatUncertain formats u's value, uncertainty and units according to
+// the Formatter's configuration.
+func (f *Formatter) FormatUncertain(u Uncertain) string {
+	var sb strings.Builder
+	if f.conciseSigma && u.Sigma == 0 {
+		sb.WriteString(f.valueFn(f.valueFmt, u.V.S))
+	} else if places, digits, ok := conciseDigits(u.Sigma); f.conciseSigma && ok {
+		v := u.V.S
+		if places < 0 {
+			scale := math.Pow10(-places)
+			v = math.Round(v/scale) * scale
+			for ; places < 0; places++ {
+				digits *= 10
+			}
+		}
+		sb.WriteString(f.valueFn(fmt.Sprintf("%%.%df", places), v))
+		fmt.Fprintf(&sb, "(%d)", digits)
+	} else {
+		sb.WriteString(f.valueFn(f.valueFmt, u.V.S))
+		sb.WriteString(" ± ")
+		sb.WriteString(f.valueFn(f.valueFmt, u.Sigma))
+	}
+	f.writeUnits(&sb, u.V.U, math.Abs(u.V.S) != 1)
+	return sb.String()
+}
+
+// FormatInterval formats i's bounds and units according to the
+// Formatter's configuration, as in "10–12 mm".
+func (f *Formatter) FormatInterval(i Interval) string {
+	var sb strings.Builder
+	sb.WriteString(f.valueFn(f.valueFmt, i.Lo))
+	sb.WriteRune(EnDash)
+	sb.WriteString(f.valueFn(f.valueFmt, i.Hi))
+	f.writeUnits(&sb, i.U, true)
+	return sb.String()
+}
+
+// FormatComplex formats c's value and units according to the Formatter's
+// configuration, as in "50+j30 Ω", or "58.3∠31° Ω" if WithPolar is given.
+func (f *Formatter) FormatComplex(c Complex) string {
+	var sb strings.Builder
+	if f.polar {
+		sb.WriteString(f.valueFn(f.valueFmt, cmplx.Abs(c.C)))
+		sb.WriteRune(AngleSign)
+		sb.WriteString(f.valueFn(f.valueFmt, degrees(cmplx.Phase(c.C))))
+		sb.WriteString("°")
+	} else {
+		sb.WriteString(f.valueFn(f.valueFmt, real(c.C)))
+		if im := imag(c.C); math.Signbit(im) {
+			sb.WriteString("-j")
+			sb.WriteString(f.valueFn(f.valueFmt, -im))
+		} else {
+			sb.WriteString("+j")
+			sb.WriteString(f.valueFn(f.valueFmt, im))
+		}
+	}
+	f.writeUnits(&sb, c.U, true)
+	return sb.String()
+}
+
+// FormatVector formats v's components and units according to the
+// Formatter's configuration, as in "(1, 2, 3) m/s".
+func (f *Formatter) FormatVector(v Vector) string {
+	parts := make([]string, len(v.C))
+	for i, x := range v.C {
+		parts[i] = f.valueFn(f.valueFmt, x)
+	}
+	var sb strings.Builder
+	sb.WriteString("(" + strings.Join(parts, ", ") + ")")
+	f.writeUnits(&sb, v.U, true)
+	return sb.String()
+}
+
+// maxConcisePlaces limits the decimal places, either side of the decimal
+// point, that concise uncertainties are written to, so that the digits
+// fit comfortably in an int64 and the value remains readable.
+const maxConcisePlaces = 15
+
+// conciseDigits returns the number of decimal places to which a value with
+// uncertainty sigma should be written, and sigma in units of the last of
+// those places.  Sigma is kept to two significant digits if the first is
+// 1, and to one otherwise.  A single digit that rounds up to 10, as with
+// 0.0096, leaves a rounded sigma beginning with 1, and so is written with
+// two.  Returns false if sigma is not positive and finite, or needs more
+// than maxConcisePlaces places.
+func conciseDigits(sigma float64) (places int, digits int64, ok bool) {
+	if !(sigma > 0) || math.IsInf(sigma, 0) {
+		return 0, 0, false
+	}
+	// The shortest representation gives sigma's own first digit, rather
+	// than that of sigma rounded to some number of digits.
+	s := strconv.FormatFloat(sigma, 'e', -1, 64)
+	e, _ := strconv.Atoi(s[strings.IndexByte(s, 'e')+1:])
+	places = -e
+	if s[0] == '1' {
+		places++
+	}
+	d := math.Round(sigma * math.Pow10(places))
+	if places < -maxConcisePlaces || places > maxConcisePlaces {
+		return 0, 0, false
+	}
+	return places, int64(d), true
+}
+
 func contains(u Units, list []Units) bool {
 	for _, x := range list {
 		if u.Equal(x) {
@@ -335,14 +633,22 @@ func contains(u Units, list []Units) bool {
 // Sprintf-style formatting template, and then formats the units according
 // to the Formatter's configuration.
 func (f *Formatter) Sprintf(tmpl string, v Qualified) string {
+	if f.simplifier != nil {
+		v = f.simplifier.rewrite(FromQualified(v))
+	}
 	var sb strings.Builder
 	sb.WriteString(f.valueFn(tmpl, v.Value()))
-	u := v.Units()
+	f.writeUnits(&sb, v.Units(), math.Abs(v.Value()) != 1)
+	return sb.String()
+}
+
+// writeUnits writes the units u that follow a value.  If plural is true,
+// the value is not 1 or -1.
+func (f *Formatter) writeUnits(sb *strings.Builder, u Units, plural bool) {
 	if !u.Empty() {
-		if !contains(u, f.noGapFor) {
+		if !contains(u, f.noGapFor) && !f.noSpace(u) {
 			sb.WriteString(f.beforeUnits)
 		}
-		sb.WriteString(f.FormatUnits(u))
+		sb.WriteString(f.formatUnits(u, plural))
 	}
-	return sb.String()
 }
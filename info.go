@@ -0,0 +1,189 @@
+package unit
+
+import (
+	"fmt"
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Info describes a unit for presentation to people.  It is attached to a
+// unit in a Registry with Registry.Describe.
+type Info struct {
+	Name        string // singular long name, as in "metre"
+	Plural      string // plural long name, as in "metres"
+	Description string // what the unit measures or how it's defined
+	System      string // the system of units it belongs to, as in "SI"
+
+	// NoPrefixes prevents Registry.Find from applying prefixes to the
+	// unit, as for the minute, whose "mmin" is better left unparsed.
+	NoPrefixes bool
+
+	// NoSpace asks formatters using the registry (see WithRegistry) to
+	// write the unit's symbol with no space between the value and the
+	// symbol, as in "90°".
+	NoSpace bool
+}
+
+// UnitInfo returns the Info attached to u in r or the nearest of its
+// parents by Describe, and false if there is none.  Prefixed units have no
+// Info of their own.
+func (r *Registry) UnitInfo(u Unit) (Info, bool) {
+	if u == nil || !reflect.TypeOf(u).Comparable() {
+		return Info{}, false
+	}
+	for q := r; q != nil; q = q.Parent {
+		q.mu.RLock()
+		i, ok := q.infos[u]
+		q.mu.RUnlock()
+		if ok {
+			return i, true
+		}
+	}
+	return Info{}, false
+}
+
+// Describe attaches info to the unit m in r, and registers its long name
+// and plural (by default its name with "s" added) in r as aliases for it,
+// so that "5 metres" can be parsed.  Names already registered for m are
+// skipped, as are names Parse could not read as a single word, such as
+// "nautical mile", which are used only for formatting.  Returns m.  Panics
+// if m is not a named unit, if a name is already registered in r for a
+// different unit, or if r is frozen.
+func (r *Registry) Describe(m Maker, info Info) Maker {
+	u := m.Unit()
+	if u == nil || !reflect.TypeOf(u).Comparable() {
+		panic(fmt.Sprintf("Describe can only be used for named units, got %q", m.Units()))
+	}
+	plural := info.Plural
+	if plural == "" && info.Name != "" {
+		plural = info.Name + "s"
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.frozen {
+		panic(fmt.Sprintf("Describe %q: %v", u.Symbol(), Frozen))
+	}
+	var names []string
+	for _, n := range []string{info.Name, plural} {
+		if n == "" || n == u.Symbol() || !isWord(n) {
+			continue
+		}
+		if got := r.syms[n]; got != nil && got.Unit() != u {
+			panic(fmt.Sprintf("Unit %q already registered", n))
+		}
+		names = append(names, n)
+	}
+	if r.syms == nil {
+		r.syms = make(map[string]Maker)
+	}
+	for _, n := range names {
+		r.syms[n] = m
+		r.markName(n)
+	}
+	r.describe(u, info)
+	return m
+}
+
+// DescribePrefix registers name as the long name of the prefix registered
+// in r as symbol, as in "kilo" for "k", so that formatters writing long
+// names (see WithLongNames) write "kilometre" for km, and Find applies it
+// to the long names of units, as in "kilometres".  Panics if symbol is not
+// a prefix registered in r, if name is already registered as a prefix, or
+// if r is frozen.
+func (r *Registry) DescribePrefix(symbol, name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.frozen {
+		panic(fmt.Sprintf("DescribePrefix %q: %v", symbol, Frozen))
+	}
+	p := r.pref.get(symbol)
+	if p == nil || p.alias {
+		panic(fmt.Sprintf("Prefix %q not registered", symbol))
+	}
+	if r.pref.get(name) != nil {
+		panic(fmt.Sprintf("Prefix %q already registered", name))
+	}
+	p.name = name
+	r.pref.put(name, p.m, p.mult, true).long = true
+}
+
+// markName records that the symbol s is a long name.  The caller must hold
+// r.mu.
+func (r *Registry) markName(s string) {
+	if r.names == nil {
+		r.names = make(map[string]bool)
+	}
+	r.names[s] = true
+}
+
+// describe attaches i to u.  The caller must hold r.mu.
+func (r *Registry) describe(u Unit, i Info) {
+	if r.infos == nil {
+		r.infos = make(map[Unit]Info)
+	}
+	r.infos[u] = i
+}
+
+// prefixName returns the long name of the prefix of p registered in r or
+// its parents, or "" if there is none.
+func (r *Registry) prefixName(p prefixType) string {
+	for q := r; q != nil; q = q.Parent {
+		q.mu.RLock()
+		e := q.pref.get(p.prefix)
+		var name string
+		if e != nil && e.mult == p.mult {
+			name = e.name
+		}
+		q.mu.RUnlock()
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// isWord returns true if Parse reads s as a single unit name.
+func isWord(s string) bool {
+	for i, c := range s {
+		if c == utf8.RuneError || !unicode.IsOneOf(unitAfter, c) || (i == 0 && !unicode.IsOneOf(unitFirst, c)) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// longName returns u's long name in the formatter's registry, or its
+// plural if plural is true, or its symbol if it has none.  If u has a
+// long name but no plural, the plural is formed by adding "s".  Prefixed
+// units are named by their prefix's long name followed by the unit's.
+func (f *Formatter) longName(u Unit, plural bool) string {
+	inner, prefix := u, ""
+	if p, ok := u.(prefixType); ok {
+		if prefix = f.registry.prefixName(p); prefix == "" {
+			return u.Symbol()
+		}
+		inner = p.inner
+	}
+	i, ok := f.registry.UnitInfo(inner)
+	switch {
+	case !ok || i.Name == "":
+		return u.Symbol()
+	case !plural:
+		return prefix + i.Name
+	case i.Plural != "":
+		return prefix + i.Plural
+	}
+	return prefix + i.Name + "s"
+}
+
+// noSpace returns true if us is a single unit whose Info asks that its
+// symbol be written with no space before it, and the formatter is writing
+// symbols.
+func (f *Formatter) noSpace(us Units) bool {
+	if f.longNames || len(us.N) != 1 || len(us.D) != 0 {
+		return false
+	}
+	i, _ := f.registry.UnitInfo(us.N[0])
+	return i.NoSpace
+}
@@ -0,0 +1,122 @@
+package unit_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dnesting/unit"
+)
+
+func TestDescribe(t *testing.T) {
+	r := unit.NewRegistry("", nil)
+	m := r.Primitive("m")
+	s := r.Primitive("s")
+	r.Prefix("k", 1000)
+	r.Prefix("m", 0.001)
+	r.DescribePrefix("k", "kilo")
+	min := r.Derive("min", s(60))
+	deg := r.Primitive("°")
+	r.Describe(m, unit.Info{Name: "metre", Description: "length", System: "SI"})
+	r.Describe(s, unit.Info{Name: "second", Plural: "seconds"})
+	r.Describe(min, unit.Info{Name: "minute", NoPrefixes: true})
+	r.Describe(deg, unit.Info{Name: "degree", NoSpace: true})
+	nmi := r.Describe(r.Derive("nmi", m(1852)), unit.Info{Name: "nautical mile"})
+
+	if i, ok := r.UnitInfo(m.Unit()); !ok || i.Name != "metre" || i.System != "SI" {
+		t.Errorf("m should be described as metre, got %+v", i)
+	}
+	if _, ok := r.UnitInfo(r.Find("km").Unit()); ok {
+		t.Errorf("km should have no Info of its own")
+	}
+	// Info belongs to the registry it was attached in.
+	other := unit.NewRegistry("", nil)
+	other.Describe(m, unit.Info{Name: "meter"})
+	if i, _ := r.UnitInfo(m.Unit()); i.Name != "metre" {
+		t.Errorf("m should still be described as metre in r, got %+v", i)
+	}
+	if i, _ := unit.NewRegistry("", other).UnitInfo(m.Unit()); i.Name != "meter" {
+		t.Errorf("Info should be found through the parent, got %+v", i)
+	}
+	for _, x := range []struct {
+		expr     string
+		expected unit.Value
+	}{
+		{"5 metres", m(5)},
+		{"5 metre/second", m.Div(s)(5)},
+		{"2 minutes", min(2)},
+		{"2 kilometres", r.Find("km")(2)},
+		{"2 kilom", unit.Value{}},          // long prefixes apply to long names
+		{"2 kmetre", unit.Value{}},         // and symbols to symbols
+		{"2 nautical miles", unit.Value{}}, // names with spaces are not registered
+	} {
+		v, err := unit.Parse(x.expr, r, true)
+		if x.expected.U.Empty() {
+			if err == nil {
+				t.Errorf("%q should fail, got %v", x.expr, v)
+			}
+			continue
+		}
+		if err != nil || !v.Equal(x.expected) {
+			t.Errorf("%q should give %v, got %v (err=%v)", x.expr, x.expected, v, err)
+		}
+	}
+	if r.Find("nautical mile") != nil {
+		t.Errorf("nautical mile should not be registered")
+	}
+	if got := r.Find("kmin"); got != nil {
+		t.Errorf("min should not take prefixes, got %v", got)
+	}
+
+	f := unit.NewFormatter(unit.WithLongNames(r))
+	for _, x := range []struct {
+		v        unit.Value
+		expected string
+	}{
+		{m(1), "1 metre"},
+		{m(-1), "-1 metre"},
+		{m(2.5), "2.5 metres"},
+		{m.Div(s.Pow(2))(9.8), "9.8 metres per second^2"},
+		{s.Mul(m)(3), "3 metre seconds"},
+		{unit.Scalar(1).Div(s)(4), "4 per second"},
+		{min(3), "3 minutes"},
+		{r.Find("km")(3), "3 kilometres"},
+		{r.Find("km").Mul(s)(1), "1 kilometre second"},
+		{r.Find("mm")(3), "3 mm"}, // m has no long name as a prefix
+		{nmi(2), "2 nautical miles"},
+		{deg(90), "90 degrees"},
+	} {
+		if got := f.Format(x.v); got != x.expected {
+			t.Errorf("expected %q, got %q", x.expected, got)
+		}
+	}
+	if got := unit.NewFormatter(unit.WithRegistry(r)).Format(deg(90)); got != "90°" {
+		t.Errorf("NoSpace units should have no gap, got %q", got)
+	}
+	if got := unit.NewFormatter().Format(deg(90)); got != "90 °" {
+		t.Errorf("NoSpace should only apply with the registry, got %q", got)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Describe should panic when a name is taken by another unit")
+			}
+		}()
+		r.Describe(s, unit.Info{Name: "metre"})
+	}()
+	// Describing a unit again with the same names is allowed.
+	r.Describe(m, unit.Info{Name: "metre", Plural: "metres"})
+}
+
+func ExampleRegistry_Describe() {
+	r := unit.NewRegistry("", nil)
+	ft := r.Derive("ft", r.Primitive("m")(0.3048))
+	r.Describe(ft, unit.Info{Name: "foot", Plural: "feet"})
+
+	v, _ := unit.Parse("6 feet", r, true)
+	fmt.Println(v)
+	fmt.Println(unit.NewFormatter(unit.WithLongNames(r)).Format(v))
+	// Output:
+	// 6 ft
+	// 6 feet
+}
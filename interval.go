@@ -0,0 +1,222 @@
+package unit
+
+import (
+	"math"
+	"math/big"
+)
+
+// Interval is a range of values [Lo, Hi] sharing the units U.  Arithmetic
+// on intervals is conservative: the result contains every value that could
+// result from applying the operation to values within the operands, and
+// endpoints are rounded outward so that floating point error never
+// narrows the range.  Interval implements Qualified, representing the
+// midpoint of the range.
+type Interval struct {
+	Lo, Hi float64
+	U      Units
+}
+
+// NewInterval returns the interval between lo and hi, in lo's units.  If
+// hi is less than lo, they are swapped.  Returns false if lo and hi are
+// not conformable.
+func NewInterval(lo, hi Value) (Interval, bool) {
+	hi, ok := lo.conform(hi)
+	if !ok {
+		return Interval{}, false
+	}
+	if hi.S < lo.S {
+		lo.S, hi.S = hi.S, lo.S
+	}
+	return Interval{Lo: lo.S, Hi: hi.S, U: lo.U}, true
+}
+
+// Lower returns the lower bound of i.
+func (i Interval) Lower() Value { return Value{S: i.Lo, U: i.U} }
+
+// Upper returns the upper bound of i.
+func (i Interval) Upper() Value { return Value{S: i.Hi, U: i.U} }
+
+// Value returns the midpoint of i.
+func (i Interval) Value() float64 { return i.Lo + (i.Hi-i.Lo)/2 }
+
+// Units returns the Units for i.
+func (i Interval) Units() Units { return i.U }
+
+// Width returns the difference between the bounds of i.
+func (i Interval) Width() float64 { return i.Hi - i.Lo }
+
+func (i Interval) String() string {
+	return DefaultFormatter.FormatInterval(i)
+}
+
+// outward returns the interval spanning the scalars of vs, each the
+// correctly rounded result of a single floating point operation on exact
+// operands, widened by the one step of rounding error that may have
+// introduced.
+func outward(vs ...Value) Interval {
+	r := Interval{Lo: math.Inf(1), Hi: math.Inf(-1), U: vs[0].U}
+	for _, v := range vs {
+		r.Lo = math.Min(r.Lo, v.S)
+		r.Hi = math.Max(r.Hi, v.S)
+	}
+	r.Lo = math.Nextafter(r.Lo, math.Inf(-1))
+	r.Hi = math.Nextafter(r.Hi, math.Inf(1))
+	return r
+}
+
+// convertErr bounds the relative rounding error of a conversion computed
+// in floating point, which multiplies the factors of the units' chains of
+// derivations.  It allows for chains of a few dozen steps.
+const convertErr = 64 * 0x1p-53
+
+// Convert converts i into the units of wanted, as described in
+// Value.Convert, rounding the bounds outward.  Where the conversion
+// factors are known exactly (see Value.Exact), the bounds are the nearest
+// float64 values outside the exact results; otherwise they are widened by
+// the error the conversion may have accumulated.
+func (i Interval) Convert(wanted Maker) (result Interval, remain Units) {
+	wu := wanted.Units()
+	if i.U.Equal(wu) {
+		return i, Units{}
+	}
+	lo, remain := i.Lower().Convert(wanted)
+	if !remain.Empty() {
+		return Interval{Lo: lo.S, Hi: lo.S, U: lo.U}, remain
+	}
+	hi, _ := i.Upper().Convert(wanted)
+	if hi.S < lo.S {
+		lo, hi = hi, lo
+	}
+	result = Interval{U: lo.U}
+	if i.U.logarithmic() == nil && wu.logarithmic() == nil {
+		elo := i.Lower().Exact().convertRat(wu)
+		ehi := i.Upper().Exact().convertRat(wu)
+		if elo != nil && ehi != nil {
+			if ehi.Cmp(elo) < 0 {
+				elo, ehi = ehi, elo
+			}
+			result.Lo, result.Hi = ratDown(elo), ratUp(ehi)
+			return result, remain
+		}
+	}
+	result.Lo = lo.S - convertBound(i.U, wu, i.Lo, lo.S)
+	result.Hi = hi.S + convertBound(i.U, wu, i.Hi, hi.S)
+	return outward(Value{S: result.Lo, U: lo.U}, Value{S: result.Hi, U: lo.U}), remain
+}
+
+// convertBound bounds the rounding error of x, converted from units a to
+// b in floating point, giving r.  Affine offsets are included, since
+// subtracting them may cancel most of the result's digits.
+func convertBound(a, b Units, x, r float64) float64 {
+	if a.logarithmic() != nil || b.logarithmic() != nil {
+		return convertErr * (math.Abs(r) + 1)
+	}
+	from, to := offsets(a, b)
+	f := a.dims().div(b.dims()).f
+	return convertErr * (math.Abs((x+from)*f) + math.Abs(to))
+}
+
+// ratDown returns the greatest float64 no greater than r.
+func ratDown(r *big.Rat) float64 {
+	f, _ := r.Float64()
+	if new(big.Rat).SetFloat64(f).Cmp(r) > 0 {
+		f = math.Nextafter(f, math.Inf(-1))
+	}
+	return f
+}
+
+// ratUp returns the least float64 no less than r.
+func ratUp(r *big.Rat) float64 {
+	f, _ := r.Float64()
+	if new(big.Rat).SetFloat64(f).Cmp(r) < 0 {
+		f = math.Nextafter(f, math.Inf(1))
+	}
+	return f
+}
+
+// Reduce reduces the units for i to primitive units, rounding the bounds
+// outward as Convert does.
+func (i Interval) Reduce() Interval {
+	r, _ := i.Convert(i.Lower().Reduce().U.Make)
+	return r
+}
+
+// conform converts b into units suitable for adding to or subtracting
+// from a, as Value.Add and Value.Sub would, rounding the bounds outward.
+func (a Interval) conform(b Interval) (Interval, bool) {
+	to := a.U
+	if a.U.logarithmic() != nil || b.U.absolute() != nil {
+		// Levels, and absolute readings subtracted from absolute
+		// readings, are left for Value to interpret.
+		return b, true
+	}
+	if aa := a.U.absolute(); aa != nil {
+		to = aa.delta.Units()
+	}
+	r, remain := b.Convert(to.Make)
+	return r, remain.Empty()
+}
+
+// Add adds a and b, as described in Value.Add.  Returns false if the units
+// are not equivalent.
+func (a Interval) Add(b Interval) (r Interval, ok bool) {
+	if a.U.absolute() == nil && b.U.absolute() != nil {
+		a, b = b, a
+	}
+	if b, ok = a.conform(b); !ok {
+		return
+	}
+	lo, ok := a.Lower().Add(b.Lower())
+	if !ok {
+		return
+	}
+	hi, _ := a.Upper().Add(b.Upper())
+	return outward(lo, hi), true
+}
+
+// Sub subtracts b from a, as described in Value.Sub.  Returns false if the
+// units are not equivalent.
+func (a Interval) Sub(b Interval) (r Interval, ok bool) {
+	if b, ok = a.conform(b); !ok {
+		return
+	}
+	lo, ok := a.Lower().Sub(b.Upper())
+	if !ok {
+		return
+	}
+	hi, _ := a.Upper().Sub(b.Lower())
+	return outward(lo, hi), true
+}
+
+// Mul multiplies a and b.
+func (a Interval) Mul(b Interval) Interval {
+	al, ah, bl, bh := a.Lower(), a.Upper(), b.Lower(), b.Upper()
+	return outward(al.Mul(bl), al.Mul(bh), ah.Mul(bl), ah.Mul(bh))
+}
+
+// Div divides a by b.  If b contains zero, the result is unbounded.
+func (a Interval) Div(b Interval) Interval {
+	al, ah, bl, bh := a.Lower(), a.Upper(), b.Lower(), b.Upper()
+	if b.Lo <= 0 && b.Hi >= 0 {
+		return Interval{Lo: math.Inf(-1), Hi: math.Inf(1), U: a.U.Div(b.U)}
+	}
+	return outward(al.Div(bl), al.Div(bh), ah.Div(bl), ah.Div(bh))
+}
+
+// Contains returns true if v, converted into i's units, lies within i.
+// Returns false if v is not conformable with i.
+func (i Interval) Contains(v Qualified) bool {
+	c, ok := Value{U: i.U}.conform(FromQualified(v))
+	return ok && i.Lo <= c.S && c.S <= i.Hi
+}
+
+// Encloses returns true if every value in b lies within i.
+func (i Interval) Encloses(b Interval) bool {
+	return i.Contains(b.Lower()) && i.Contains(b.Upper())
+}
+
+// Overlaps returns true if i and b have any values in common.
+func (i Interval) Overlaps(b Interval) bool {
+	bc, remain := b.Convert(i.U.Make)
+	return remain.Empty() && bc.Lo <= i.Hi && i.Lo <= bc.Hi
+}
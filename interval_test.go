@@ -0,0 +1,166 @@
+package unit_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/dnesting/unit"
+)
+
+func interval(lo, hi unit.Value) unit.Interval {
+	i, ok := unit.NewInterval(lo, hi)
+	if !ok {
+		panic(fmt.Sprintf("%v and %v are not conformable", lo, hi))
+	}
+	return i
+}
+
+func TestIntervalArithmetic(t *testing.T) {
+	m := unit.Primitive("m")
+	mm := unit.Derive("mm", m(0.001))
+	s := unit.Primitive("s")
+
+	a := interval(m(1), m(2))
+	b := interval(m(-3), m(4))
+
+	for _, x := range []struct {
+		desc   string
+		r      unit.Interval
+		lo, hi unit.Value
+	}{
+		{"swapped", interval(m(2), m(1)), m(1), m(2)},
+		{"add", must2(a.Add(b)), m(-2), m(6)},
+		{"add converted", must2(a.Add(interval(mm(10), mm(20)))), m(1.01), m(2.02)},
+		{"sub", must2(a.Sub(b)), m(-3), m(5)},
+		{"mul", a.Mul(b), m.Pow(2)(-6), m.Pow(2)(8)},
+		{"div", a.Div(interval(s(2), s(4))), m.Div(s)(0.25), m.Div(s)(1)},
+	} {
+		lo, hi := x.r.Lower(), x.r.Upper()
+		if !lo.Approx(x.lo, 1e-9) || !hi.Approx(x.hi, 1e-9) || lo.S > x.lo.S || hi.S < x.hi.S {
+			t.Errorf("%s: expected %v–%v, got %v", x.desc, x.lo, x.hi, x.r)
+		}
+	}
+
+	if r := a.Div(b); !math.IsInf(r.Lo, -1) || !math.IsInf(r.Hi, 1) {
+		t.Errorf("dividing by an interval containing zero should be unbounded, got %v", r)
+	}
+	if r, ok := a.Add(interval(s(1), s(2))); ok {
+		t.Errorf("adding m and s should fail, got %v", r)
+	}
+	if _, ok := unit.NewInterval(m(1), s(2)); ok {
+		t.Errorf("interval between m and s should fail")
+	}
+}
+
+func must2(i unit.Interval, ok bool) unit.Interval {
+	if !ok {
+		panic("operation failed")
+	}
+	return i
+}
+
+func TestIntervalConvert(t *testing.T) {
+	m := unit.Primitive("m")
+	mm := unit.Derive("mm", m(0.001))
+	in := unit.Derive("in", mm(25.4))
+
+	i := interval(in(0.1), in(0.3))
+	r, remain := i.Convert(mm)
+	if !remain.Empty() || !r.Units().Equal(mm.Units()) || r.Lo > 2.54 || r.Hi < 7.62 || r.Lo < 2.5399 || r.Hi > 7.6201 {
+		t.Errorf("%v should convert to 2.54–7.62 mm, got %v (remain=%q)", i, r, remain)
+	}
+	if !r.Contains(in(0.1)) || !r.Contains(in(0.3)) || !r.Encloses(i) {
+		t.Errorf("%v should enclose %v", r, i)
+	}
+	if red := i.Reduce(); !red.Units().Equal(m.Units()) || !red.Encloses(i) {
+		t.Errorf("%v should reduce to an interval in m enclosing it, got %v", i, red)
+	}
+	if _, remain := i.Convert(unit.Primitive("s")); remain.Empty() {
+		t.Errorf("%v should not convert to s", i)
+	}
+}
+
+func TestIntervalRounding(t *testing.T) {
+	m := unit.Primitive("m")
+	mm := unit.Derive("mm", m(0.001))
+	ft := unit.Derive("ft", m(0.3048))
+	yd := unit.Derive("yd", ft(3))
+	mi := unit.Derive("mi", yd(1760))
+
+	// The bounds enclose the exact results of converting through a chain
+	// of derivations, which a single step outward from the floating point
+	// results would not.
+	r, _ := interval(mi(4.889), mi(94.86)).Convert(mm)
+	lo, hi := new(big.Rat).SetFloat64(r.Lo), new(big.Rat).SetFloat64(r.Hi)
+	if lo.Cmp(big.NewRat(7868082816, 1000)) > 0 || hi.Cmp(big.NewRat(15266237184, 100)) < 0 {
+		t.Errorf("4.889–94.86 mi should convert to an interval enclosing 7868082.816–152662371.84 mm, got %v", r)
+	}
+
+	// Repeated conversions never lose the original interval, even across
+	// affine offsets that cancel most of the digits.
+	k := unit.Primitive("K")
+	c := unit.Affine("°C", unit.Derive("Δ°C", k), 273.15)
+	f := unit.Affine("°F", unit.Derive("Δ°F", k(5.0/9)), 459.67)
+	orig := interval(c(0.01), c(0.02))
+	i := orig
+	for n := 0; n < 10; n++ {
+		i, _ = i.Convert(k)
+		i, _ = i.Convert(f)
+		i, _ = i.Convert(c)
+		if !i.Encloses(orig) || i.Width()-orig.Width() > 1e-9 {
+			t.Fatalf("after %d round trips, %v should narrowly enclose %v", n+1, i, orig)
+		}
+	}
+	if r, ok := interval(k(0), k(1)).Add(orig); !ok || !r.Units().Equal(c.Units()) || !r.Contains(c(0.01)) || !r.Contains(c(1.02)) {
+		t.Errorf("0–1 K + %v should give 0.01–1.02 °C, got %v (ok=%v)", orig, r, ok)
+	}
+}
+
+func TestIntervalContains(t *testing.T) {
+	m := unit.Primitive("m")
+	mm := unit.Derive("mm", m(0.001))
+	s := unit.Primitive("s")
+
+	i := interval(mm(10), mm(12))
+	for _, x := range []struct {
+		v        unit.Value
+		expected bool
+	}{
+		{mm(10), true},
+		{mm(11), true},
+		{m(0.012), true},
+		{mm(9), false},
+		{m(1), false},
+		{s(0.011), false},
+	} {
+		if got := i.Contains(x.v); got != x.expected {
+			t.Errorf("%v.Contains(%v) should be %v", i, x.v, x.expected)
+		}
+	}
+
+	for _, x := range []struct {
+		b                  unit.Interval
+		encloses, overlaps bool
+	}{
+		{interval(mm(10.5), mm(11)), true, true},
+		{interval(m(0.011), m(0.013)), false, true},
+		{interval(mm(13), mm(14)), false, false},
+		{interval(s(0.01), s(0.012)), false, false},
+	} {
+		if got := i.Encloses(x.b); got != x.encloses {
+			t.Errorf("%v.Encloses(%v) should be %v", i, x.b, x.encloses)
+		}
+		if got := i.Overlaps(x.b); got != x.overlaps {
+			t.Errorf("%v.Overlaps(%v) should be %v", i, x.b, x.overlaps)
+		}
+	}
+}
+
+func ExampleInterval() {
+	mm := unit.Primitive("mm")
+	i, _ := unit.NewInterval(mm(10), mm(12))
+	fmt.Println(i)
+	// Output: 10–12 mm
+}
@@ -0,0 +1,124 @@
+package unit
+
+import (
+	"math"
+	"sort"
+)
+
+// Kind distinguishes kinds of quantity that reduce to the same primitive
+// units but are not interchangeable, such as frequency (Hz) and
+// radioactive activity (Bq), or energy (J) and torque (N m).  Units
+// derived with Kind.Derive are of that kind, and values can be marked
+// with a kind using Value.As.  Values of one kind cannot be converted,
+// compared or added to values of another, though values whose units
+// have no kind may be freely converted to and from either.
+type Kind struct {
+	name  string
+	units Units
+}
+
+// NewKind creates a new kind of quantity named name, whose values are
+// expressed in the given units by preference.  If units is nil, the first
+// unit derived from the kind with Derive will be used.
+func NewKind(name string, units Maker) *Kind {
+	k := &Kind{name: name}
+	if units != nil {
+		k.units = units.Units()
+	}
+	return k
+}
+
+func (k *Kind) String() string { return k.name }
+
+// Units returns the units that values of this kind are expressed in by
+// preference, which formatters use in place of units that differ from
+// them only in name.
+func (k *Kind) Units() Units { return k.units }
+
+// Derive creates a Maker associated with a new unit named symbol and
+// derived from value, whose values are of kind k.
+func (k *Kind) Derive(symbol string, value Qualified) Maker {
+	u := newUnit(symbol, FromQualified(value))
+	u.kind = k
+	if k.units.Empty() {
+		k.units = u.units
+	}
+	return u.Make
+}
+
+// kinded is implemented by the unit types in this package that can be
+// of a kind.
+type kinded interface {
+	unitKind() *Kind
+}
+
+// Kind returns the kind of quantity described by us: the kind us was
+// marked with, if any, or else the kind of its unit if us is a single
+// unit.  Returns nil if us is not of any particular kind.
+func (us Units) Kind() *Kind {
+	if us.K != nil {
+		return us.K
+	}
+	if len(us.N) == 1 && len(us.D) == 0 {
+		if k, ok := us.N[0].(kinded); ok {
+			return k.unitKind()
+		}
+	}
+	return nil
+}
+
+// kindsAgree returns true unless a and b are both of a kind, and their
+// kinds differ.
+func kindsAgree(a, b Units) bool {
+	ka, kb := a.Kind(), b.Kind()
+	return ka == nil || kb == nil || ka == kb
+}
+
+// kindRemainder returns the remainder of a conversion from a to b refused
+// because their kinds disagree.  The units are not cancelled, so that the
+// remainder is not empty even if a and b differ only in kind.
+func kindRemainder(a, b Units) Units {
+	r := a.mul(b.Recip())
+	sort.Sort(unitList(r.N))
+	sort.Sort(unitList(r.D))
+	return r
+}
+
+// preferred returns the units in which us should be displayed: the units
+// of the kind us is marked with, if they differ from us only in name.
+func (us Units) preferred() Units {
+	k := us.K
+	if k == nil || k.units.Empty() {
+		return us
+	}
+	if unitList(us.N).Equal(k.units.N) && unitList(us.D).Equal(k.units.D) {
+		return us
+	}
+	if len(us.N) == 1 && len(us.D) == 0 {
+		if uk, ok := us.N[0].(kinded); ok && uk.unitKind() == k {
+			return us
+		}
+	}
+	d := us.dims().div(k.units.dims())
+	if len(d.t) > 0 || math.Abs(d.f-1) > 1e-12 {
+		return us
+	}
+	return k.units
+}
+
+// Kind returns the kind of quantity a represents, as described in
+// Units.Kind.
+func (a Value) Kind() *Kind { return a.U.Kind() }
+
+// As returns a marked as being a quantity of kind k, replacing any kind
+// it had.  This is the means by which a value can be converted from one
+// kind to another, as in treating a dose equivalent in Sv as an absorbed
+// dose in Gy.  If k is nil, any kind a was marked with is removed.
+// Returns false if a's units do not conform to those of k.
+func (a Value) As(k *Kind) (Value, bool) {
+	if k != nil && !k.units.Empty() && !a.U.dims().conforms(k.units.dims()) {
+		return Value{}, false
+	}
+	a.U.K = k
+	return a, true
+}
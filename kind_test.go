@@ -0,0 +1,138 @@
+package unit_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dnesting/unit"
+)
+
+func TestKind(t *testing.T) {
+	s := unit.Primitive("s")
+	freq := unit.NewKind("frequency", nil)
+	act := unit.NewKind("activity", nil)
+	hz := freq.Derive("Hz", unit.Scalar(1).Div(s))
+	bq := act.Derive("Bq", unit.Scalar(1).Div(s))
+	k := unit.Prefix("k", 1000)
+
+	for _, x := range []struct {
+		v        unit.Qualified
+		expected *unit.Kind
+	}{
+		{hz(1), freq},
+		{bq(1), act},
+		{k(bq)(1), act},
+		{s(1), nil},
+		{bq.Mul(s)(1), nil},
+		{bq.Pow(2)(1), nil},
+		{bq(2).MulN(3), act},
+		{unit.MustConvert(bq(2), k(bq)), act},
+	} {
+		if got := x.v.Units().Kind(); got != x.expected {
+			t.Errorf("%v should be of kind %v, got %v", x.v, x.expected, got)
+		}
+	}
+
+	// Conversions within a kind, and to and from units of no kind, work.
+	for _, x := range []struct {
+		from     unit.Value
+		to       unit.Maker
+		expected unit.Value
+	}{
+		{bq(2000), k(bq), k(bq)(2)},
+		{bq(5), unit.Scalar(1).Div(s), unit.Scalar(1).Div(s)(5)},
+		{unit.Scalar(1).Div(s)(5), hz, hz(5)},
+	} {
+		if r, remain := x.from.Convert(x.to); !remain.Empty() || !r.Equal(x.expected) {
+			t.Errorf("%v converted to %v should give %v, got %v (remain %q)", x.from, x.to, x.expected, r, remain)
+		}
+	}
+
+	// Conversions across kinds do not.
+	if r, remain := hz(5).Convert(bq); remain.Empty() {
+		t.Errorf("Hz should not convert to Bq, got %v", r)
+	}
+	if _, ok := hz(5).Add(bq(1)); ok {
+		t.Errorf("Hz and Bq should not add")
+	}
+	if hz(5).Equal(bq(5)) {
+		t.Errorf("5 Hz should not equal 5 Bq")
+	}
+	if _, err := unit.NewConverter(hz, k(bq)); err == nil {
+		t.Errorf("NewConverter(Hz, kBq) should fail")
+	}
+	_, err := hz(5).ConvertErr(bq)
+	var ce *unit.ConversionError
+	if !errors.As(err, &ce) || ce.FromKind != freq || ce.ToKind != act {
+		t.Fatalf("converting Hz to Bq should give a ConversionError naming their kinds, got %v", err)
+	}
+	if got, want := ce.Describe(nil), "expected activity, got frequency"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	// Unless the value is explicitly given the new kind.
+	v, ok := hz(5).As(act)
+	if !ok {
+		t.Fatalf("%v should be convertible to activity", hz(5))
+	}
+	if r, remain := v.Convert(bq); !remain.Empty() || !r.Equal(bq(5)) {
+		t.Errorf("%v converted to Bq should give 5 Bq, got %v (remain %q)", v, r, remain)
+	}
+	if _, ok := s(1).As(act); ok {
+		t.Errorf("s should not be markable as activity")
+	}
+}
+
+func TestKindFormat(t *testing.T) {
+	s := unit.Primitive("s")
+	freq := unit.NewKind("frequency", nil)
+	act := unit.NewKind("activity", nil)
+	hz := freq.Derive("Hz", unit.Scalar(1).Div(s))
+	act.Derive("Bq", unit.Scalar(1).Div(s))
+	ms := unit.Derive("ms", s(0.001))
+	torque := unit.NewKind("torque", nil)
+	n := unit.Primitive("N")
+	m := unit.Primitive("m")
+
+	mark := func(v unit.Value, k *unit.Kind) unit.Value {
+		r, ok := v.As(k)
+		if !ok {
+			t.Fatalf("%v should be markable as %v", v, k)
+		}
+		return r
+	}
+	for _, x := range []struct {
+		v        unit.Value
+		expected string
+	}{
+		{mark(unit.Scalar(1).Div(s)(5), act), "5 Bq"},
+		{mark(unit.Scalar(1).Div(s)(5), freq), "5 Hz"},
+		{mark(hz(5), act), "5 Bq"},
+		{mark(unit.Scalar(1).Div(ms)(5), act), "5 /ms"},
+		{mark(n.Mul(m)(5), torque), "5 N m"},
+		{unit.Scalar(1).Div(s)(5), "5 /s"},
+	} {
+		if got := x.v.String(); got != x.expected {
+			t.Errorf("expected %q, got %q", x.expected, got)
+		}
+	}
+}
+
+func ExampleKind() {
+	s := unit.Primitive("s")
+	frequency := unit.NewKind("frequency", nil)
+	activity := unit.NewKind("activity", nil)
+	hz := frequency.Derive("Hz", unit.Scalar(1).Div(s))
+	bq := activity.Derive("Bq", unit.Scalar(1).Div(s))
+
+	_, err := hz(5).ConvertErr(bq)
+	fmt.Println(err)
+
+	decays := unit.Scalar(1).Div(s)(300)
+	decays, _ = decays.As(activity)
+	fmt.Println(decays)
+	// Output:
+	// unit mismatch: expected "Bq" (activity), got "Hz" (frequency)
+	// 300 Bq
+}
@@ -0,0 +1,154 @@
+package unit
+
+import (
+	"fmt"
+	"math"
+)
+
+// logType is a logarithmic unit, whose values are levels relative to a
+// reference quantity, such as dBm (relative to 1 mW).  Logarithmic units
+// with a unitless reference of 1, such as dB, are gains.
+//
+// Logarithmic units are treated as primitive by Reduce, since their values
+// cannot be scaled linearly into other units.  Convert, Compare, Add and
+// Sub recognize them and operate in the log domain.
+type logType struct {
+	symbol string
+	ref    Value
+	step   float64 // the size of one unit, in decibels
+	field  bool    // whether the reference is a root-power (field) quantity
+	units  Units
+	canon  dims
+}
+
+func (u *logType) Symbol() string       { return u.symbol }
+func (u *logType) Deriv() Value         { return Value{S: 1} }
+func (u *logType) Units() Units         { return u.units }
+func (u *logType) Value() float64       { return 1 }
+func (u *logType) Make(v float64) Value { return u.units.Make(v) }
+func (u *logType) Equal(o Unit) bool    { return unitEqual(u, o) }
+func (u *logType) dims() dims           { return u.canon }
+func (u *logType) String() string {
+	return fmt.Sprintf("Log(%q = %gdB re %v)", u.symbol, u.step, u.ref)
+}
+
+// Logarithmic creates a Maker associated with a new logarithmic unit named
+// symbol.  Values are levels relative to ref, where one unit is decibels
+// decibels.  If field is true, ref is a root-power (field) quantity, such
+// as a voltage or pressure, for which 20 dB is a factor of 10; otherwise
+// it is a power quantity, for which 10 dB is a factor of 10.  For
+// instance, dBm is Logarithmic("dBm", Milli(Watt)(1), 1, false), and the
+// neper is Logarithmic("Np", Unity, 20/math.Ln10, true).
+//
+// A logarithmic unit with a unitless reference of 1 is a gain.  Adding a
+// gain to a level (or to another gain) shifts it by the same number of
+// decibels, while adding two levels sums them as powers.  Convert moves
+// between levels and their linear equivalents, and between gains of
+// different sizes.
+func Logarithmic(symbol string, ref Qualified, decibels float64, field bool) Maker {
+	u := &logType{
+		symbol: symbol,
+		ref:    FromQualified(ref),
+		step:   decibels,
+		field:  field,
+	}
+	u.units = Units{N: []Unit{u}}
+	u.canon = primitiveDims(u)
+	return u.Make
+}
+
+// logarithmic returns the logarithmic unit if us consists of a single
+// logarithmic unit in its numerator, or nil otherwise.
+func (us Units) logarithmic() *logType {
+	if len(us.N) == 1 && len(us.D) == 0 {
+		if u, ok := us.N[0].(*logType); ok {
+			return u
+		}
+	}
+	return nil
+}
+
+// gain returns true if u is a gain rather than a level.
+func (u *logType) gain() bool {
+	return u.ref.U.Empty() && u.ref.S == 1
+}
+
+// ratio returns the linear ratio to u.ref represented by the level x.
+func (u *logType) ratio(x float64) float64 {
+	e := x * u.step / 10
+	if u.field {
+		e /= 2
+	}
+	return math.Pow(10, e)
+}
+
+// level returns the level of u representing the linear ratio r to u.ref.
+func (u *logType) level(r float64) float64 {
+	x := 10 * math.Log10(r) / u.step
+	if u.field {
+		x *= 2
+	}
+	return x
+}
+
+// power returns the power ratio represented by the level x, regardless of
+// whether u describes a field quantity.
+func (u *logType) power(x float64) float64 {
+	return math.Pow(10, x*u.step/10)
+}
+
+// fromPower returns the level of u representing the power ratio p.
+func (u *logType) fromPower(p float64) float64 {
+	return 10 * math.Log10(p) / u.step
+}
+
+// convertLog converts a into the units wu, where at least one of la and
+// lw (the logarithmic units of a and wu) is non-nil.  Returns false if
+// the units are not conformable.
+func (a Value) convertLog(la *logType, wu Units, lw *logType) (Value, bool) {
+	if la != nil && lw != nil && la.gain() && lw.gain() {
+		return Value{S: a.S * la.step / lw.step, U: wu}, true
+	}
+	lin := a
+	if la != nil {
+		lin = la.ref.MulN(la.ratio(a.S))
+	}
+	if lw == nil {
+		r, remain := lin.Convert(wu.Make)
+		return r, remain.Empty()
+	}
+	r, remain := lin.Convert(lw.ref.U.Make)
+	if !remain.Empty() {
+		return Value{}, false
+	}
+	return Value{S: lw.level(r.S / lw.ref.S), U: wu}, true
+}
+
+// addLog adds b to a (or subtracts it, if sign is negative), where la is
+// a's logarithmic unit.
+func (a Value) addLog(la *logType, b Value, sign float64) (r Value, ok bool) {
+	lb := b.U.logarithmic()
+	if lb != nil && lb.gain() {
+		// Gains shift a by the same number of decibels.
+		return Value{S: a.S + sign*b.S*lb.step/la.step, U: a.U}, true
+	}
+	if la.gain() {
+		// A gain plus a level is a shifted level; anything else fails.
+		if lb != nil && sign > 0 {
+			return b.addLog(lb, a, 1)
+		}
+		return
+	}
+	// Levels are summed as powers.
+	bb, remain := b.Convert(a.U.Make)
+	if !remain.Empty() {
+		return
+	}
+	p := la.power(a.S) + sign*la.power(bb.S)
+	if p <= 0 {
+		// The difference of two levels has no level unless it is a
+		// positive power.
+		return
+	}
+	return Value{S: la.fromPower(p), U: a.U}, true
+}
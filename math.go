@@ -0,0 +1,143 @@
+package unit
+
+import (
+	"math"
+	"math/big"
+)
+
+// rounding describes a way of rounding a quotient to an integer, both in
+// floating point and exactly.
+type rounding struct {
+	f func(float64) float64
+	r func(*big.Rat) *big.Int
+}
+
+var (
+	floorMode = rounding{math.Floor, ratFloor}
+	ceilMode  = rounding{math.Ceil, ratCeil}
+	roundMode = rounding{math.Round, ratRound}
+	truncMode = rounding{math.Trunc, ratTrunc}
+	evenMode  = rounding{math.RoundToEven, ratRoundToEven}
+)
+
+// ratFloor returns the greatest integer not greater than q.  Since q's
+// denominator is positive, Euclidean division rounds toward -∞.
+func ratFloor(q *big.Rat) *big.Int { return new(big.Int).Div(q.Num(), q.Denom()) }
+
+func ratCeil(q *big.Rat) *big.Int {
+	n := ratFloor(new(big.Rat).Neg(q))
+	return n.Neg(n)
+}
+
+func ratTrunc(q *big.Rat) *big.Int { return new(big.Int).Quo(q.Num(), q.Denom()) }
+
+// ratRound rounds q to the nearest integer, rounding half away from zero.
+func ratRound(q *big.Rat) *big.Int {
+	half := big.NewRat(1, 2)
+	if q.Sign() < 0 {
+		return ratCeil(new(big.Rat).Sub(q, half))
+	}
+	return ratFloor(new(big.Rat).Add(q, half))
+}
+
+// ratRoundToEven rounds q to the nearest integer, rounding half to even.
+func ratRoundToEven(q *big.Rat) *big.Int {
+	h := new(big.Rat).Add(q, big.NewRat(1, 2))
+	n := ratFloor(h)
+	if h.IsInt() && n.Bit(0) == 1 {
+		n.Sub(n, big.NewInt(1))
+	}
+	return n
+}
+
+// increment converts b into a's units, as Add does, so that it may be
+// used as a step for a.  If a is an absolute reading on an affine scale,
+// b is converted into a's delta units.
+func (a Value) increment(b Qualified) (Value, bool) {
+	to := a.U
+	if aa := a.U.absolute(); aa != nil {
+		to = aa.delta.Units()
+	}
+	bv := FromQualified(b)
+	if a.R != nil {
+		bv = bv.Exact()
+	}
+	return Value{U: to}.conform(bv)
+}
+
+// roundTo returns a rounded to a multiple of inc using m.
+func (a Value) roundTo(inc Qualified, m rounding) (Value, bool) {
+	b, ok := a.increment(inc)
+	if !ok {
+		return Value{}, false
+	}
+	if a.R != nil || b.R != nil {
+		if q := quoRat(a.exact(), b.exact()); q != nil {
+			n := new(big.Rat).SetInt(m.r(q))
+			return a.withRat(mulRat(n, b.exact())), true
+		}
+	}
+	return Value{S: m.f(a.S/b.S) * b.S, U: a.U}, true
+}
+
+// Floor returns the greatest multiple of inc that is not greater than a,
+// in a's units.  For instance, 1.37 m floored to 5 cm is 1.35 m.  Returns
+// false if inc's units are not equivalent to a's.
+func (a Value) Floor(inc Qualified) (Value, bool) { return a.roundTo(inc, floorMode) }
+
+// Ceil returns the least multiple of inc that is not less than a, in a's
+// units.  Returns false if inc's units are not equivalent to a's.
+func (a Value) Ceil(inc Qualified) (Value, bool) { return a.roundTo(inc, ceilMode) }
+
+// Round returns the multiple of inc nearest to a, in a's units, rounding
+// half away from zero.  For instance, 1.37 m rounded to 1/16 in is 53.9375
+// in, expressed in metres.  Returns false if inc's units are not
+// equivalent to a's.
+func (a Value) Round(inc Qualified) (Value, bool) { return a.roundTo(inc, roundMode) }
+
+// modulo returns a - n×b in a's units, where n is a/b rounded using m.
+func (a Value) modulo(b Qualified, m rounding, fn func(x, y float64) float64) (Value, bool) {
+	bv, ok := a.increment(b)
+	if !ok {
+		return Value{}, false
+	}
+	if a.R != nil || bv.R != nil {
+		if q := quoRat(a.exact(), bv.exact()); q != nil {
+			n := new(big.Rat).SetInt(m.r(q))
+			return a.withRat(subRat(a.exact(), mulRat(n, bv.exact()))), true
+		}
+	}
+	return Value{S: fn(a.S, bv.S), U: a.U}, true
+}
+
+// Mod returns the remainder of a divided by b, in a's units, with the
+// sign of a, as math.Mod does.  For instance, 100 min mod 1 h is 40 min.
+// Returns false if b's units are not equivalent to a's.
+func (a Value) Mod(b Qualified) (Value, bool) { return a.modulo(b, truncMode, math.Mod) }
+
+// Remainder returns the IEEE 754 remainder of a divided by b, in a's
+// units, as math.Remainder does.  Returns false if b's units are not
+// equivalent to a's.
+func (a Value) Remainder(b Qualified) (Value, bool) {
+	return a.modulo(b, evenMode, math.Remainder)
+}
+
+// Abs returns the absolute value of a.
+func (a Value) Abs() Value {
+	r := Value{S: math.Abs(a.S), U: a.U}
+	if a.R != nil {
+		r.R = new(big.Rat).Abs(a.R)
+	}
+	return r
+}
+
+// Hypot returns Sqrt(a² + b²), in a's units, taking care to avoid
+// unnecessary overflow and underflow.  Returns false if b's units are not
+// equivalent to a's.
+func (a Value) Hypot(b Qualified) (Value, bool) {
+	bv, ok := a.conform(FromQualified(b))
+	if !ok {
+		return Value{}, false
+	}
+	return Value{S: math.Hypot(a.S, bv.S), U: a.U}, true
+}
@@ -0,0 +1,113 @@
+package unit_test
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/dnesting/unit"
+)
+
+func TestRounding(t *testing.T) {
+	m := unit.Primitive("m")
+	cm := unit.Derive("cm", m(0.01))
+	in := unit.Derive("in", cm(2.54))
+	s := unit.Primitive("s")
+	dc := unit.Derive("Δ°C", unit.Primitive("K"))
+	c := unit.Affine("°C", dc, 273.15)
+
+	sixteenth := in.MakeRat(big.NewRat(1, 16))
+	for _, x := range []struct {
+		desc     string
+		fn       func(unit.Value, unit.Qualified) (unit.Value, bool)
+		a        unit.Value
+		inc      unit.Qualified
+		expected unit.Value
+	}{
+		{"round", unit.Value.Round, m(1.37), cm(5), m(1.35)},
+		{"round up", unit.Value.Round, m(1.38), cm(5), m(1.4)},
+		{"round half", unit.Value.Round, m(-1.375), cm(5), m(-1.4)},
+		{"floor", unit.Value.Floor, m(1.39), cm(5), m(1.35)},
+		{"floor negative", unit.Value.Floor, m(-1.36), cm(5), m(-1.4)},
+		{"ceil", unit.Value.Ceil, m(1.36), cm(5), m(1.4)},
+		{"ceil negative", unit.Value.Ceil, m(-1.39), cm(5), m(-1.35)},
+		{"sixteenth", unit.Value.Round, m(1.37), sixteenth, in(53.9375)},
+		{"exact", unit.Value.Round, m(1.37).Exact(), sixteenth, in(53.9375)},
+		{"exact floor", unit.Value.Floor, m(0.3).Exact(), cm(10), m(0.3)},
+		{"affine", unit.Value.Round, c(21.37), dc(0.5), c(21.5)},
+	} {
+		r, ok := x.fn(x.a, x.inc)
+		if !ok || !r.Approx(x.expected, 1e-9) || !r.Units().Equal(x.a.Units()) {
+			t.Errorf("%s: %v to %v should give %v, got %v (ok=%v)", x.desc, x.a, x.inc, x.expected, r, ok)
+		}
+	}
+
+	if r, _ := m(0.3).Exact().Floor(cm(10)); !r.Equal(m(0.3)) || r.R == nil {
+		t.Errorf("0.3 m floored to 10 cm should be exactly 0.3 m, got %v", r)
+	}
+	if r, ok := m(1).Round(s(1)); ok {
+		t.Errorf("rounding m to s should fail, got %v", r)
+	}
+}
+
+func TestMod(t *testing.T) {
+	min := unit.Primitive("min")
+	h := unit.Derive("h", min(60))
+	m := unit.Primitive("m")
+
+	for _, x := range []struct {
+		desc     string
+		fn       func(unit.Value, unit.Qualified) (unit.Value, bool)
+		a        unit.Value
+		b        unit.Qualified
+		expected unit.Value
+	}{
+		{"mod", unit.Value.Mod, min(100), h(1), min(40)},
+		{"mod negative", unit.Value.Mod, min(-100), h(1), min(-40)},
+		{"mod exact", unit.Value.Mod, min(100).Exact(), h(1), min(40)},
+		{"remainder", unit.Value.Remainder, min(100), h(1), min(-20)},
+		{"remainder exact", unit.Value.Remainder, min(90).Exact(), h(1), min(-30)},
+	} {
+		r, ok := x.fn(x.a, x.b)
+		if !ok || !r.Approx(x.expected, 1e-9) || !r.Units().Equal(x.a.Units()) {
+			t.Errorf("%s: %v and %v should give %v, got %v (ok=%v)", x.desc, x.a, x.b, x.expected, r, ok)
+		}
+	}
+	if r, ok := min(1).Mod(m(1)); ok {
+		t.Errorf("min mod m should fail, got %v", r)
+	}
+}
+
+func TestAbsHypot(t *testing.T) {
+	m := unit.Primitive("m")
+	cm := unit.Derive("cm", m(0.01))
+	s := unit.Primitive("s")
+
+	if r := m(-2).Abs(); !r.Equal(m(2)) {
+		t.Errorf("|-2 m| should be 2 m, got %v", r)
+	}
+	if r := m(-0.1).Exact().Abs(); r.R == nil || !r.Equal(m(0.1)) {
+		t.Errorf("|-0.1 m| should be exactly 0.1 m, got %v", r)
+	}
+	if r, ok := m(3).Hypot(cm(400)); !ok || !r.Approx(m(5), 1e-9) {
+		t.Errorf("hypot of 3 m and 400 cm should be 5 m, got %v (ok=%v)", r, ok)
+	}
+	if r, ok := m(3).Hypot(s(4)); ok {
+		t.Errorf("hypot of m and s should fail, got %v", r)
+	}
+}
+
+func ExampleValue_Round() {
+	m := unit.Primitive("m")
+	cm := unit.Derive("cm", m(0.01))
+	min := unit.Primitive("min")
+	h := unit.Derive("h", min(60))
+
+	r, _ := m(1.37).Exact().Round(cm(5))
+	fmt.Println(r)
+	r, _ = min(100).Mod(h(1))
+	fmt.Println(r)
+	// Output:
+	// 1.35 m
+	// 40 min
+}
@@ -0,0 +1,65 @@
+package unit
+
+import "sort"
+
+// extreme returns whichever of vs compares as want (-1 or +1) against all
+// the others.
+func extreme(want int, v Value, vs []Value) (Value, error) {
+	for _, o := range vs {
+		c, err := o.Cmp(v)
+		if err != nil {
+			return Value{}, err
+		}
+		if c == want {
+			v = o
+		}
+	}
+	return v, nil
+}
+
+// Min returns the smallest of v and vs, in its own units.  The values may
+// be in any conformable units, such as a mix of feet and metres.  Returns
+// an Incomparable error if any of them are not conformable with v.
+func Min(v Value, vs ...Value) (Value, error) {
+	return extreme(-1, v, vs)
+}
+
+// Max returns the largest of v and vs, in its own units.  The values may
+// be in any conformable units.  Returns an Incomparable error if any of
+// them are not conformable with v.
+func Max(v Value, vs ...Value) (Value, error) {
+	return extreme(1, v, vs)
+}
+
+// Sort sorts vs in increasing order, keeping the order of equal values.
+// The values may be in any conformable units, such as a mix of feet and
+// metres, and are left in their own units.  Returns an Incomparable error,
+// leaving vs unchanged, if any of them are not conformable with the first.
+func Sort(vs []Value) error {
+	if len(vs) == 0 {
+		return nil
+	}
+	keys := make([]float64, len(vs))
+	for i, v := range vs {
+		k, remain := v.Convert(vs[0].U.Make)
+		if !remain.Empty() {
+			_, err := v.Cmp(vs[0])
+			return err
+		}
+		keys[i] = k.S
+	}
+	sort.Stable(byKey{vs, keys})
+	return nil
+}
+
+type byKey struct {
+	vs   []Value
+	keys []float64
+}
+
+func (b byKey) Len() int           { return len(b.vs) }
+func (b byKey) Less(i, j int) bool { return b.keys[i] < b.keys[j] }
+func (b byKey) Swap(i, j int) {
+	b.vs[i], b.vs[j] = b.vs[j], b.vs[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
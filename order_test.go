@@ -0,0 +1,103 @@
+package unit_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dnesting/unit"
+)
+
+func TestCmp(t *testing.T) {
+	m := unit.Primitive("m")
+	ft := unit.Derive("ft", m(0.3048))
+	s := unit.Primitive("s")
+
+	for _, x := range []struct {
+		a, b     unit.Value
+		expected int
+	}{
+		{m(1), m(2), -1},
+		{m(2), m(1), 1},
+		{m(1), m(1), 0},
+		{ft(3), m(1), -1},
+		{ft(4), m(1), 1},
+		{m(0.3048), ft(1), 0},
+	} {
+		if c, err := x.a.Cmp(x.b); err != nil || c != x.expected {
+			t.Errorf("%v.Cmp(%v) should be %d, got %d (err=%v)", x.a, x.b, x.expected, c, err)
+		}
+	}
+	if _, err := m(1).Cmp(s(1)); !errors.Is(err, unit.Incomparable) {
+		t.Errorf("comparing m and s should fail with Incomparable, got %v", err)
+	}
+	if ok, err := ft(1).Compare(m(0.3048), nil); !ok || err != nil {
+		t.Errorf("1 ft should equal 0.3048 m with a nil cmpFn, got %v (err=%v)", ok, err)
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	m := unit.Primitive("m")
+	ft := unit.Derive("ft", m(0.3048))
+	s := unit.Primitive("s")
+
+	if r, err := unit.Min(m(1), ft(3), m(0.5), ft(1)); err != nil || !r.Equal(ft(1)) || !r.Units().Equal(ft.Units()) {
+		t.Errorf("Min should be 1 ft, got %v (err=%v)", r, err)
+	}
+	if r, err := unit.Max(m(1), ft(4), m(0.5)); err != nil || !r.Equal(ft(4)) {
+		t.Errorf("Max should be 4 ft, got %v (err=%v)", r, err)
+	}
+	if r, err := unit.Max(m(1)); err != nil || !r.Equal(m(1)) {
+		t.Errorf("Max of one value should be that value, got %v (err=%v)", r, err)
+	}
+	if _, err := unit.Min(m(1), s(1)); err == nil {
+		t.Errorf("Min of m and s should fail")
+	}
+}
+
+func TestSort(t *testing.T) {
+	m := unit.Primitive("m")
+	ft := unit.Derive("ft", m(0.3048))
+	s := unit.Primitive("s")
+
+	vs := []unit.Value{m(1), ft(1), ft(4), m(0.3048), m(0.5)}
+	if err := unit.Sort(vs); err != nil {
+		t.Fatal(err)
+	}
+	if e := fmt.Sprint([]unit.Value{ft(1), m(0.3048), m(0.5), m(1), ft(4)}); fmt.Sprint(vs) != e {
+		t.Errorf("expected %v, got %v", e, vs)
+	}
+
+	vs = []unit.Value{m(2), s(1), m(1)}
+	if err := unit.Sort(vs); err == nil || !vs[0].Equal(m(2)) {
+		t.Errorf("sorting m and s should fail and leave the slice unchanged, got %v (err=%v)", vs, err)
+	}
+}
+
+func TestApproxRel(t *testing.T) {
+	m := unit.Primitive("m")
+	km := unit.Derive("km", m(1000))
+
+	if !km(1e9).ApproxRel(m(1e12+1), 1e-9) {
+		t.Errorf("1e9 km should be relatively close to 1e12+1 m")
+	}
+	if m(1e-9).ApproxRel(m(2e-9), 1e-3) {
+		t.Errorf("1e-9 m should not be relatively close to 2e-9 m")
+	}
+	if !m(0).ApproxRel(m(0), 0) {
+		t.Errorf("0 m should be relatively close to itself")
+	}
+	if m(1).ApproxRel(unit.Primitive("s")(1), 1) {
+		t.Errorf("m and s should not be comparable")
+	}
+}
+
+func ExampleSort() {
+	m := unit.Primitive("m")
+	ft := unit.Derive("ft", m(0.3048))
+
+	vs := []unit.Value{m(1), ft(2), ft(4)}
+	unit.Sort(vs)
+	fmt.Println(vs)
+	// Output: [2 ft 1 m 4 ft]
+}
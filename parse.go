@@ -3,7 +3,10 @@ package unit
 import (
 	"errors"
 	"fmt"
+	"math"
+	"math/cmplx"
 	"strconv"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 )
@@ -52,30 +55,21 @@ func (p *parser) skipDigits() {
 }
 
 func (p *parser) consume(str string) bool {
-	start := p.n
-	var i int
-	for i < len(str) && p.ch == rune(str[i]) {
+	saved := *p
+	for _, c := range str {
+		if p.ch != c {
+			*p = saved
+			return false
+		}
 		p.next()
 	}
-	if i == len(str) {
-		return true
-	}
-	p.n = start
-	return false
+	return true
 }
 
 func (p *parser) isDigit() bool {
 	return unicode.IsDigit(p.ch)
 }
 
-func (p *parser) parseInt() (int64, error) {
-	start := p.n
-	for p.isDigit() {
-		p.next()
-	}
-	return strconv.ParseInt(p.value[start:p.n], 10, 64)
-}
-
 func (p *parser) parseFloat() (value float64, ok bool, err error) {
 	start := p.n
 	var commit bool
@@ -145,33 +139,120 @@ func fromSuper(r rune) int {
 }
 
 func isExponent(ch rune) bool {
+	if _, ok := fromVulgar(ch); ok {
+		return true
+	}
 	return ch == '^' || fromSuper(ch) >= 0 || ch == superMinus
 }
 
-func (p *parser) parseExponent() (exp int64, err error) {
+// maxExponent bounds the numerator and denominator of parsed exponents,
+// since a unit raised to the power n is represented by |n| copies of it.
+const maxExponent = 100
+
+// maxFractionDigits bounds the digits after the decimal point in parsed
+// exponents, so that the denominator cannot overflow.
+const maxFractionDigits = 6
+
+// parseDecimal parses an optionally signed decimal number such as "2",
+// "-1" or "0.5" and returns it as an exact fraction.
+func (p *parser) parseDecimal() (r rat, err error) {
+	start := p.n
+	if p.ch == '-' || p.ch == '+' {
+		p.next()
+	}
+	p.skipDigits()
+	digits := p.value[start:p.n]
+	den := 1
+	if p.ch == '.' {
+		p.next()
+		fstart := p.n
+		p.skipDigits()
+		if p.n-fstart > maxFractionDigits {
+			return rat{}, fmt.Errorf("offset %d: invalid exponent %q: too many digits", start, p.value[start:p.n])
+		}
+		for i := fstart; i < p.n; i++ {
+			den *= 10
+		}
+		digits += p.value[fstart:p.n]
+	}
+	n, err := strconv.ParseInt(digits, 10, 32)
+	if err != nil {
+		return rat{}, fmt.Errorf("offset %d: invalid exponent %q: %w", start, p.value[start:p.n], err)
+	}
+	return newRat(int(n), den), nil
+}
+
+// parseExponent parses exponents of the form "^2", "^-2", "^0.5",
+// "^(1/2)", "²", "⁻²" and "½".
+func (p *parser) parseExponent() (exp rat, err error) {
 	if p.ch == '^' {
 		p.next()
-		return p.parseInt()
+		if p.ch != '(' {
+			exp, err = p.parseDecimal()
+		} else {
+			p.next()
+			if exp, err = p.parseDecimal(); err != nil {
+				return rat{}, err
+			}
+			if isFraction(p.ch) {
+				p.next()
+				var den rat
+				if den, err = p.parseDecimal(); err != nil {
+					return rat{}, err
+				}
+				if den.n == 0 {
+					return rat{}, errors.New("zero denominator in exponent")
+				}
+				exp = exp.mul(newRat(den.d, den.n))
+			}
+			if p.ch != ')' {
+				return rat{}, fmt.Errorf("offset %d: expected ')' to close exponent", p.n)
+			}
+			p.next()
+		}
+		if err == nil && exp.n == 0 {
+			err = errors.New("exponent must not be zero")
+		}
+		if err == nil {
+			err = checkExponent(exp)
+		}
+		return exp, err
 	}
 	mult := 1
 	if p.ch == superMinus {
 		mult = -1
 		p.next()
 	}
+	var n int
 	for {
-		n := fromSuper(p.ch)
-		if n >= 0 {
-			exp = exp*10 + int64(n)
+		d := fromSuper(p.ch)
+		if d >= 0 {
+			if n = n*10 + d; n > maxExponent {
+				return rat{}, fmt.Errorf("exponent must be at most %d", maxExponent)
+			}
 			p.next()
 		} else {
 			break
 		}
 	}
-	if exp == 0 {
-		return 0, errors.New("exponent must be > 0")
+	exp = rat{n, 1}
+	if v, ok := fromVulgar(p.ch); ok {
+		exp = exp.add(v)
+		p.next()
+	}
+	if exp.n == 0 {
+		return rat{}, errors.New("exponent must be > 0")
+	}
+	exp.n *= mult
+	return exp, checkExponent(exp)
+}
+
+// checkExponent returns an error if exp is too large to represent.
+func checkExponent(exp rat) error {
+	if exp.n > maxExponent || -exp.n > maxExponent || exp.d > maxExponent {
+		return fmt.Errorf("exponent %v exceeds the limit of %d", exp, maxExponent)
 	}
-	exp *= int64(mult)
-	return exp, nil
+	return nil
 }
 
 var (
@@ -183,34 +264,40 @@ func (p *parser) parseUnitLine() (num, denom []Unit, err error) {
 	for unicode.IsOneOf(unitFirst, p.ch) {
 		start := p.n
 		p.next()
-		for unicode.IsOneOf(unitAfter, p.ch) {
+		for {
+			for unicode.IsOneOf(unitAfter, p.ch) {
+				p.next()
+			}
+			// A colon joins the name of a registry and a symbol it
+			// qualifies, as in "us:ft".
+			if p.ch != ':' {
+				break
+			}
+			if r, _ := utf8.DecodeRuneInString(p.value[p.n+1:]); !unicode.IsOneOf(unitFirst, r) {
+				break
+			}
 			p.next()
 		}
 		name := p.value[start:p.n]
-		if found := p.reg.Find(name); found != nil {
+		if found, err := p.reg.FindErr(name); found != nil {
 			num = append(num, found.Unit())
-		} else if p.mustExist {
-			return nil, nil, fmt.Errorf("unknown unit %q", name)
+		} else if p.mustExist || errors.Is(err, Ambiguous) {
+			return nil, nil, err
+		} else if m, err := p.reg.primitive(name); err != nil {
+			return nil, nil, err
 		} else {
-			num = append(num, p.reg.Register(Primitive(name)).Unit())
+			num = append(num, m.Unit())
 		}
 		if isExponent(p.ch) {
 			exp, err := p.parseExponent()
 			if err != nil {
 				return nil, nil, err
 			}
-			if exp < 0 {
-				// If exp is <0, we have to add the units to the denominator
-				// instead of the numerator.
-				for i := 0; i < int(exp*-1); i++ {
-					denom = append(denom, num[len(num)-1])
-				}
-				num = num[:len(num)-1]
-			} else {
-				for i := 1; i < int(exp); i++ {
-					num = append(num, num[len(num)-1])
-				}
-			}
+			// Negative exponents move the unit to the denominator, and
+			// fractional ones replace it with a root.
+			pu := num[len(num)-1].Units().powRat(exp)
+			num = append(num[:len(num)-1], pu.N...)
+			denom = append(denom, pu.D...)
 		}
 		p.skipSpaces()
 	}
@@ -221,29 +308,174 @@ func (p *parser) parseUnitLine() (num, denom []Unit, err error) {
 // values of the form "1.234 kg m/s^2" and "1.234 kg⋅m⋅s⁻²"
 // (using U+22C5 DOT OPERATOR).  If reg is provided, unit symbols will be
 // looked up in reg and used if found.  If mustExist is false, any units
-// parsed but missing from the registry will be added as primitive units.
-// Otherwise, an error will be generated.
+// parsed but missing from the registry will be added as primitive units,
+// unless the registry is frozen (see Registry.Freeze), in which case an
+// error wrapping Frozen is returned.  Otherwise, an error will be
+// generated.
+//
+// Values with an uncertainty, in either of the forms accepted by
+// ParseUncertain, are also accepted, and their central value returned.
+// Use ParseUncertain to keep the uncertainty.
 //
 // This is experimental and the API is likely to change.
 func Parse(str string, reg *Registry, mustExist bool) (val Value, err error) {
 	p := newParser(str, reg, mustExist)
 
+	start := p.n
 	scalar, gotScalar, err := p.parseFloat()
 	if err != nil {
 		return Value{}, fmt.Errorf("units parse: %w", err)
 	}
 	if !gotScalar {
 		scalar = 1
+	} else if _, err := p.parseSigma(p.value[start:p.n]); err != nil {
+		return Value{}, err
 	}
 	p.skipSpaces()
 
+	units, err := p.parseTrailingUnits()
+	if err != nil {
+		return Value{}, err
+	}
+	return Value{S: scalar, U: units}, nil
+}
+
+// parseTrailingUnits parses the units that end a value.
+func (p *parser) parseTrailingUnits() (Units, error) {
 	units, err := p.parseUnits()
 	if err != nil {
-		return Value{}, fmt.Errorf("units parse: %w", err)
+		return Units{}, fmt.Errorf("units parse: %w", err)
 	}
 	p.skipSpaces()
 	if !p.eof {
-		return Value{}, fmt.Errorf("units parse: extra text after units at %q here-> %q", p.value[:p.n], p.value[p.n:])
+		return Units{}, fmt.Errorf("units parse: extra text after units at %q here-> %q", p.value[:p.n], p.value[p.n:])
 	}
-	return Value{S: scalar, U: units}, nil
+	return units, nil
+}
+
+// resolution returns the value of one unit in the last digit of the number
+// written as s, such as 0.01 for "9.81" or 100 for "1.2e3".
+func resolution(s string) float64 {
+	var exp int
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exp, _ = strconv.Atoi(s[i+1:])
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		exp -= len(s) - i - 1
+	}
+	return math.Pow10(exp)
+}
+
+// ParseUncertain attempts to parse a value with an uncertainty contained
+// in str, as described in Parse.  It accepts values of the form
+// "9.81 ± 0.02 m/s^2", "9.81 +/- 0.02 m/s^2" and the concise "9.81(2) m/s^2",
+// where the digits in parentheses give the uncertainty in the last digits
+// of the value.  A value without an uncertainty is parsed as exact.
+//
+// This is experimental and the API is likely to change.
+func ParseUncertain(str string, reg *Registry, mustExist bool) (Uncertain, error) {
+	p := newParser(str, reg, mustExist)
+
+	start := p.n
+	scalar, gotScalar, err := p.parseFloat()
+	if err != nil {
+		return Uncertain{}, fmt.Errorf("units parse: %w", err)
+	}
+	if !gotScalar {
+		return Uncertain{}, fmt.Errorf("units parse: expected number at start of %q", str)
+	}
+	sigma, err := p.parseSigma(p.value[start:p.n])
+	if err != nil {
+		return Uncertain{}, err
+	}
+	p.skipSpaces()
+
+	units, err := p.parseTrailingUnits()
+	if err != nil {
+		return Uncertain{}, err
+	}
+	return Value{S: scalar, U: units}.PlusMinus(sigma), nil
+}
+
+// parseSigma parses the uncertainty that may follow number, the text of
+// the number just parsed, either in parentheses or after "±" or "+/-".
+// Returns 0 if there is none.
+func (p *parser) parseSigma(number string) (sigma float64, err error) {
+	if p.ch == '(' {
+		p.next()
+		dstart := p.n
+		p.skipDigits()
+		digits := p.value[dstart:p.n]
+		if digits == "" || p.ch != ')' {
+			return 0, fmt.Errorf("units parse: offset %d: expected uncertainty digits and ')'", p.n)
+		}
+		p.next()
+		d, _ := strconv.ParseFloat(digits, 64)
+		sigma = d * resolution(number)
+	}
+	p.skipSpaces()
+	if sigma == 0 && (p.consume("±") || p.consume("+/-")) {
+		p.skipSpaces()
+		var ok bool
+		if sigma, ok, err = p.parseFloat(); err != nil {
+			return 0, fmt.Errorf("units parse: %w", err)
+		} else if !ok {
+			return 0, fmt.Errorf("units parse: offset %d: expected uncertainty after ±", p.n)
+		}
+	}
+	return sigma, nil
+}
+
+// ParseComplex attempts to parse a complex value contained in str, as
+// described in Parse.  It accepts values in rectangular form, such as
+// "50+j30 Ω" or "50-j30 Ω", and in polar form, such as "58.3∠31° Ω", where
+// the phase angle is in degrees.
+//
+// This is experimental and the API is likely to change.
+func ParseComplex(str string, reg *Registry, mustExist bool) (Complex, error) {
+	p := newParser(str, reg, mustExist)
+
+	a, ok, err := p.parseFloat()
+	if err != nil {
+		return Complex{}, fmt.Errorf("units parse: %w", err)
+	}
+	if !ok {
+		return Complex{}, fmt.Errorf("units parse: expected number at start of %q", str)
+	}
+	var c complex128
+	switch {
+	case p.consume("+j"), p.consume("-j"):
+		sign := 1.0
+		if p.value[p.n-2] == '-' {
+			sign = -1
+		}
+		b, ok, err := p.parseFloat()
+		if err == nil && !ok {
+			err = fmt.Errorf("offset %d: expected imaginary part", p.n)
+		}
+		if err != nil {
+			return Complex{}, fmt.Errorf("units parse: %w", err)
+		}
+		c = complex(a, sign*b)
+	case p.consume(string(AngleSign)):
+		deg, ok, err := p.parseFloat()
+		if err == nil && !ok {
+			err = fmt.Errorf("offset %d: expected phase angle", p.n)
+		}
+		if err != nil {
+			return Complex{}, fmt.Errorf("units parse: %w", err)
+		}
+		p.consume("°")
+		c = cmplx.Rect(a, deg*math.Pi/180)
+	default:
+		c = complex(a, 0)
+	}
+	p.skipSpaces()
+
+	units, err := p.parseTrailingUnits()
+	if err != nil {
+		return Complex{}, err
+	}
+	return Complex{C: c, U: units}, nil
 }
@@ -53,6 +53,20 @@ func TestParse(t *testing.T) {
 		{"23 km/ks", true, m.Div(s)(23), "", "with prefix, reduced"},
 
 		{"1.234 kg⋅m⋅s⁻²", true, k(g).Mul(m).Div(s.Pow(2))(1.234), "", "with unicode"},
+		{"2 m^-2", true, unit.Scalar(1).Div(m.Pow(2))(2), "", "negative exponent"},
+		{"2 m^(1/2)", true, m.PowRat(1, 2)(2), "", "fractional exponent"},
+		{"2 m^(-3/2)", true, m.PowRat(-3, 2)(2), "", "negative fractional exponent"},
+		{"2 m^0.5", true, m.PowRat(1, 2)(2), "", "decimal exponent"},
+		{"2 m/s^0.5", true, m.Div(s.PowRat(1, 2))(2), "", "decimal exponent in denominator"},
+		{"2 m⋅s⁻½", true, m.Div(s.PowRat(1, 2))(2), "", "vulgar fraction exponent"},
+		{"2 m^(1/2) m^(1/2)", true, m(2), "", "fractional exponents combined"},
+		{"2 m^0", true, nil, "must not be zero", "zero exponent"},
+		{"2 m^(1/0)", true, nil, "zero denominator", "zero denominator"},
+		{"2 m^(1/2", true, nil, "expected ')'", "unclosed exponent"},
+		{"2 m^0." + strings.Repeat("0", 70) + "1", true, nil, "too many digits", "long decimal exponent"},
+		{"2 m^(100000/3)", true, nil, "exceeds the limit", "large exponent"},
+		{"2 m^(1/1000)", true, nil, "exceeds the limit", "large root"},
+		{"2 m⁻⁹⁹⁹⁹⁹⁹⁹⁹⁹⁹⁹⁹⁹⁹⁹⁹⁹⁹⁹⁹", true, nil, "at most", "large superscript exponent"},
 	}
 
 	for _, c := range cases {
@@ -76,3 +90,49 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParseQualified(t *testing.T) {
+	base := unit.NewRegistry("base", nil)
+	survey := unit.NewRegistry("survey", base)
+	m := base.Primitive("m")
+	ft := base.Derive("ft", m(0.3048))
+	sft := survey.Derive("ft", m(1200.0/3937))
+
+	if got := survey.QualifiedSymbol(sft.Unit()); got != "survey:ft" {
+		t.Errorf("expected survey:ft, got %q", got)
+	}
+	// Qualifying a unit depends only on the registry asked, not on the
+	// order units were registered in.
+	other := unit.NewRegistry("other", nil)
+	other.Register(ft)
+	if got := other.QualifiedSymbol(ft.Unit()); got != "other:ft" {
+		t.Errorf("expected other:ft, got %q", got)
+	}
+	if got := survey.QualifiedSymbol(ft.Unit()); got != "base:ft" {
+		t.Errorf("expected base:ft, got %q", got)
+	}
+	for _, x := range []struct {
+		test     string
+		expected unit.Value
+	}{
+		{"2 ft", sft(2)},
+		{"2 survey:ft", sft(2)},
+		{"2 base:ft", ft(2)},
+		{"2 base:ft/survey:ft", ft.Div(sft)(2)},
+	} {
+		v, err := unit.Parse(x.test, survey, true)
+		if err != nil || !v.U.Equal(x.expected.U) || v.S != x.expected.S {
+			t.Errorf("Parse(%q) should give %v, got %v (err=%v)", x.test, x.expected, v, err)
+		}
+	}
+	if _, err := unit.Parse("2 other:ft", survey, true); err == nil {
+		t.Errorf("unknown registries should not be found")
+	}
+	f := unit.NewFormatter(unit.WithRegistry(survey))
+	if got, want := f.FormatUnits(ft.Div(sft).Units()), "base:ft/survey:ft"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got, want := ft.Div(sft).Units().String(), "ft[0.3048 m]/ft[0.3048006096012192 m]"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
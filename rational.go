@@ -0,0 +1,284 @@
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+)
+
+// Uneven is returned (wrapped) when units cannot be raised to a fractional
+// power without introducing new fractional exponents, such as taking the
+// square root of "m".
+var Uneven = errors.New("units cannot be split evenly")
+
+// rat is a small rational number used to represent unit exponents.  The
+// denominator is always positive and the fraction is always in lowest terms.
+type rat struct {
+	n, d int
+}
+
+func gcd(a, b int) int {
+	if a < 0 {
+		a = -a
+	}
+	if b < 0 {
+		b = -b
+	}
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// newRat returns n/d in lowest terms.  It panics if d is zero.
+func newRat(n, d int) rat {
+	if d == 0 {
+		panic("unit: zero denominator in exponent")
+	}
+	if d < 0 {
+		n, d = -n, -d
+	}
+	if g := gcd(n, d); g > 1 {
+		n, d = n/g, d/g
+	}
+	return rat{n, d}
+}
+
+func (a rat) add(b rat) rat   { return newRat(a.n*b.d+b.n*a.d, a.d*b.d) }
+func (a rat) mul(b rat) rat   { return newRat(a.n*b.n, a.d*b.d) }
+func (a rat) sub(b rat) rat   { return a.add(b.neg()) }
+func (a rat) quo(b rat) rat   { return newRat(a.n*b.d, a.d*b.n) }
+func (a rat) neg() rat        { return rat{-a.n, a.d} }
+func (a rat) less(b rat) bool { return a.n*b.d < b.n*a.d }
+func (a rat) abs() rat {
+	if a.n < 0 {
+		return a.neg()
+	}
+	return a
+}
+func (a rat) isInt() bool    { return a.d == 1 }
+func (a rat) float() float64 { return float64(a.n) / float64(a.d) }
+func (a rat) String() string {
+	if a.d == 1 {
+		return fmt.Sprint(a.n)
+	}
+	return fmt.Sprintf("%d/%d", a.n, a.d)
+}
+
+// powf raises x to the power p.  Unlike math.Pow, odd roots of negative
+// numbers are permitted, so that powf(-8, 1/3) is -2.
+func powf(x float64, p rat) float64 {
+	if x < 0 && p.d%2 == 1 {
+		r := math.Pow(-x, p.float())
+		if p.n%2 != 0 {
+			r = -r
+		}
+		return r
+	}
+	return math.Pow(x, p.float())
+}
+
+// rootType is the den-th root of another unit.  A unit raised to the
+// fractional power n/den is represented as n copies of its den-th root,
+// in the same way that integer powers are represented by repeating a unit.
+type rootType struct {
+	inner Unit
+	den   int
+	units Units
+	canon dims
+}
+
+// newRoot returns the den-th root of u.  Roots of roots are collapsed, so
+// that the root of the square root of u is the fourth root of u.
+func newRoot(u Unit, den int) *rootType {
+	if r, ok := u.(*rootType); ok {
+		u, den = r.inner, r.den*den
+	}
+	r := &rootType{inner: u, den: den}
+	r.units = Units{N: []Unit{r}}
+	r.canon = unitDims(u).pow(rat{1, den})
+	if ur := unitDims(u).r; ur != nil && ur.Cmp(big.NewRat(1, 1)) == 0 {
+		r.canon.r = ur
+	}
+	return r
+}
+
+func (u *rootType) Symbol() string       { return fmt.Sprintf("%s^(1/%d)", u.inner.Symbol(), u.den) }
+func (u *rootType) Units() Units         { return u.units }
+func (u *rootType) Value() float64       { return 1 }
+func (u *rootType) Make(v float64) Value { return u.units.Make(v) }
+func (u *rootType) dims() dims           { return u.canon }
+func (u *rootType) String() string       { return fmt.Sprintf("Root(%d, %v)", u.den, u.inner) }
+
+// Deriv returns the root of the inner unit's derivation.  The root of a
+// primitive unit is itself treated as primitive.
+func (u *rootType) Deriv() Value {
+	if IsPrimitive(u.inner) {
+		return Value{S: 1}
+	}
+	d := u.inner.Deriv()
+	p := rat{1, u.den}
+	return Value{S: powf(d.S, p), U: d.U.powRat(p)}
+}
+
+func (u *rootType) Equal(o Unit) bool {
+	if r, ok := o.(*rootType); ok {
+		return u.den == r.den && u.inner.Equal(r.inner)
+	}
+	return false
+}
+
+// term is a unit and the (possibly fractional) exponent it is raised to.
+type term struct {
+	u Unit
+	e rat
+}
+
+// terms aggregates the units in us by their base unit, returning each
+// base unit along with its total exponent.  Roots are expressed as
+// fractional exponents of their inner unit.
+func (us Units) terms() []term {
+	var ts []term
+	add := func(u Unit, sign int) {
+		if u == nil {
+			return
+		}
+		e := rat{sign, 1}
+		if r, ok := u.(*rootType); ok {
+			u, e = r.inner, newRat(sign, r.den)
+		}
+		for i := range ts {
+			if ts[i].u.Equal(u) {
+				ts[i].e = ts[i].e.add(e)
+				return
+			}
+		}
+		ts = append(ts, term{u, e})
+	}
+	for _, u := range us.N {
+		add(u, 1)
+	}
+	for _, u := range us.D {
+		add(u, -1)
+	}
+	return ts
+}
+
+// fromTerms builds sorted Units from a list of terms.  Terms with an
+// exponent of n/d are represented by |n| copies of the d-th root of the
+// unit.
+func fromTerms(ts []term) Units {
+	var r Units
+	for _, t := range ts {
+		if t.e.n == 0 {
+			continue
+		}
+		u := t.u
+		if !t.e.isInt() {
+			u = newRoot(u, t.e.d)
+		}
+		n, list := t.e.n, &r.N
+		if n < 0 {
+			n, list = -n, &r.D
+		}
+		for i := 0; i < n; i++ {
+			*list = append(*list, u)
+		}
+	}
+	sort.Sort(unitList(r.N))
+	sort.Sort(unitList(r.D))
+	return r
+}
+
+func (us Units) hasRoots() bool {
+	for _, l := range [][]Unit{us.N, us.D} {
+		for _, u := range l {
+			if _, ok := u.(*rootType); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// powRat raises us to the power p, introducing fractional exponents
+// where necessary.
+func (us Units) powRat(p rat) Units {
+	ts := us.terms()
+	for i := range ts {
+		ts[i].e = ts[i].e.mul(p)
+	}
+	return fromTerms(ts)
+}
+
+// powRatEven raises us to the power p, provided doing so does not leave any
+// unit with an exponent less evenly divided than it was before.  If it
+// would, the offending term is returned.
+func (us Units) powRatEven(p rat) (Units, *term) {
+	ts := us.terms()
+	for i := range ts {
+		e := ts[i].e.mul(p)
+		if ts[i].e.d%e.d != 0 {
+			return Units{}, &term{ts[i].u, e}
+		}
+		ts[i].e = e
+	}
+	return fromTerms(ts), nil
+}
+
+// PowRat raises us to the rational power num/den.  If the units as
+// written cannot be split evenly (for instance, the square root of "J"),
+// they are reduced to primitive units and tried again.  The return type is
+// a Value since the act of reducing may introduce a multiplier.  Returns
+// an error wrapping Uneven if the reduced units cannot be split evenly
+// either, and an error if den is 0.
+func (us Units) PowRat(num, den int) (Value, error) {
+	if den == 0 {
+		return Value{}, fmt.Errorf("%q^(%d/%d): zero denominator", us, num, den)
+	}
+	p := newRat(num, den)
+	if r, bad := us.powRatEven(p); bad == nil {
+		return Value{S: 1, U: r}, nil
+	}
+	red := us.Reduce()
+	r, bad := red.U.powRatEven(p)
+	if bad != nil {
+		return Value{}, fmt.Errorf("%w: %q^(%v) would leave %q with exponent %v", Uneven, us, p, bad.u.Symbol(), bad.e)
+	}
+	return Value{S: powf(red.S, p), U: r}, nil
+}
+
+// PowRat raises a to the rational power num/den.  Units are handled as
+// described in Units.PowRat.  Odd roots of negative values are permitted.
+func (a Value) PowRat(num, den int) (Value, error) {
+	r, err := a.U.PowRat(num, den)
+	if err != nil {
+		return Value{}, err
+	}
+	r.S *= powf(a.S, newRat(num, den))
+	return r, nil
+}
+
+// Root returns the n-th root of a, as described in PowRat.
+func (a Value) Root(n int) (Value, error) {
+	return a.PowRat(1, n)
+}
+
+// Sqrt returns the square root of a, as described in PowRat.  For example,
+// the square root of "4 m^2/s^2" is "2 m/s".
+func (a Value) Sqrt() (Value, error) {
+	return a.PowRat(1, 2)
+}
+
+// PowRat returns a Maker that raises itself to the rational power num/den.
+// Unlike Units.PowRat, units that cannot be split evenly are kept with
+// fractional exponents, which allows units such as the "V/Hz^(1/2)" used
+// for noise densities.  Panics if den is 0.
+func (m Maker) PowRat(num, den int) Maker {
+	p := newRat(num, den)
+	v := m(1)
+	s, u := powf(v.S, p), v.U.powRat(p)
+	return func(f float64) Value { return Value{S: f * s, U: u} }
+}
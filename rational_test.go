@@ -0,0 +1,122 @@
+package unit_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dnesting/unit"
+)
+
+func TestPowRat(t *testing.T) {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+	kg := unit.Primitive("kg")
+	ha := unit.Derive("ha", m.Pow(2)(10000))
+	j := unit.Derive("J", kg.Mul(m.Pow(2)).Div(s.Pow(2)))
+
+	for _, c := range []struct {
+		desc     string
+		a        unit.Value
+		num, den int
+		expected unit.Value
+	}{
+		{"sqrt", m.Pow(2).Div(s.Pow(2))(16), 1, 2, m.Div(s)(4)},
+		{"cube root", m.Pow(3)(-8), 1, 3, m(-2)},
+		{"three halves", m.Pow(4)(4), 3, 2, m.Pow(6)(8)},
+		{"negative", m.Pow(2)(4), -1, 2, unit.Scalar(1).Div(m)(0.5)},
+		{"reduced", ha(1), 1, 2, m(100)},
+		{"fractional", m.PowRat(3, 2)(8), 2, 3, m(4)},
+	} {
+		t.Run(c.desc, func(t *testing.T) {
+			r, err := c.a.PowRat(c.num, c.den)
+			if err != nil {
+				t.Fatalf("%q.PowRat(%d, %d) returned error %v", c.a, c.num, c.den, err)
+			}
+			if !r.Approx(c.expected, 0.000001) {
+				t.Errorf("%q.PowRat(%d, %d) should give %q, got %q", c.a, c.num, c.den, c.expected, r)
+			}
+		})
+	}
+
+	for _, a := range []unit.Value{m(4), j(4), m.PowRat(1, 2)(4)} {
+		r, err := a.Sqrt()
+		if !errors.Is(err, unit.Uneven) {
+			t.Errorf("%q.Sqrt() should fail with Uneven, got %q, %v", a, r, err)
+		}
+	}
+
+	if _, err := m(4).PowRat(1, 0); err == nil {
+		t.Errorf("PowRat(1, 0) should fail")
+	}
+}
+
+func TestRootUnits(t *testing.T) {
+	s := unit.Primitive("s")
+	hz := unit.Derive("Hz", unit.Scalar(1).Div(s))
+	v := unit.Primitive("V")
+
+	rthz := hz.PowRat(1, 2)
+	if e, r := hz.Units(), rthz.Mul(rthz).Units(); !e.Equal(r) {
+		t.Errorf("Hz^(1/2) squared should be %q, got %q", e, r)
+	}
+	if e, r := hz.Pow(3).Units(), rthz.Pow(6).Units(); !e.Equal(r) {
+		t.Errorf("Hz^(1/2) to the sixth should be %q, got %q", e, r)
+	}
+	if e, r := "Hz^(3/2)", hz.Mul(rthz).Units().String(); e != r {
+		t.Errorf("Hz Hz^(1/2) should be %q, got %q", e, r)
+	}
+
+	nd := v.Div(rthz)(3)
+	if e, r := "3 V/Hz^(1/2)", nd.String(); e != r {
+		t.Errorf("expected %q, got %q", e, r)
+	}
+	psd := nd.Pow(2)
+	if e := v.Pow(2).Div(hz)(9); !psd.Equal(e) {
+		t.Errorf("%q squared should be %q, got %q", nd, e, psd)
+	}
+	if e := s.PowRat(1, 2)(3); !nd.Equal(e.Mul(v)) {
+		t.Errorf("%q should be equivalent to %q", nd, e.Mul(v))
+	}
+}
+
+func TestFormatRoots(t *testing.T) {
+	hz := unit.Primitive("Hz")
+	v := unit.Primitive("V")
+	nd := v.Div(hz.PowRat(1, 2))(3)
+
+	for _, c := range []struct {
+		desc     string
+		expected string
+		opts     []unit.FormatOpt
+	}{
+		{"basic", "3 V/Hz^(1/2)", opts()},
+		{"decimal", "3 V/Hz^0.5", opts(unit.WithDecimalPowers())},
+		{"nofraction", "3 V Hz^(-1/2)", opts(unit.WithNoFraction())},
+		{"unicode", "3 V⁄Hz½", opts(unit.WithUnicode())},
+		{"unicode-nofrac", "3 V⋅Hz⁻½", opts(unit.WithUnicode(), unit.WithNoFraction())},
+		{"mathml", "<mn>3</mn> <mfrac><mrow><mi>V</mi></mrow><mrow><msup><mi>Hz</mi><mfrac><mn>1</mn><mn>2</mn></mfrac></msup></mrow></mfrac>",
+			opts(unit.WithMathML())},
+	} {
+		t.Run(c.desc, func(t *testing.T) {
+			actual := unit.NewFormatter(c.opts...).Format(nd)
+			if c.expected != actual {
+				t.Errorf("expected %q, got %q", c.expected, actual)
+			}
+		})
+	}
+}
+
+func ExampleValue_Sqrt() {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+
+	v, err := m.Pow(2).Div(s.Pow(2))(16).Sqrt()
+	fmt.Println(v, err)
+
+	_, err = m(16).Sqrt()
+	fmt.Println(err)
+	// Output:
+	// 4 m/s <nil>
+	// units cannot be split evenly: "m"^(1/2) would leave "m" with exponent 1/2
+}
@@ -1,22 +1,61 @@
 package unit
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 )
 
+// Frozen is returned when registering a unit in a Registry that has been
+// frozen (see Registry.Freeze).
+var Frozen = errors.New("registry is frozen")
+
 // Registry is an experimental type for recording unit symbols for lookup later.
+// A Registry is safe for concurrent use by multiple goroutines.
 //
 // This type is under development and will likely change.
 type Registry struct {
-	name   string
+	Name   string // qualifies the symbols registered in the registry
+	mu     sync.RWMutex
+	frozen bool
 	syms   map[string]Maker
-	pref   map[string]func(m Maker) Maker
+	names  map[string]bool // symbols in syms that are long names
+	infos  map[Unit]Info
+	pref   prefixTrie
 	Parent *Registry
 }
 
+// NewRegistry creates a Registry named name, which qualifies the symbols
+// registered in it (see QualifiedSymbol).  Symbols not found in the
+// registry are looked up in parent, if it is non-nil.
 func NewRegistry(name string, parent *Registry) *Registry {
-	return &Registry{Parent: parent}
+	return &Registry{Name: name, Parent: parent}
+}
+
+// QualifiedSymbol returns u's symbol qualified by the name of the nearest
+// of r and its parents that u is registered in under its symbol, as in
+// "us:ft", which FindErr accepts in place of the symbol.  Prefixed units
+// are qualified by the registry of the unit they prefix.  If u is not
+// registered in a named registry, its symbol is returned unqualified.
+func (r *Registry) QualifiedSymbol(u Unit) string {
+	inner := u
+	if p, ok := u.(prefixType); ok {
+		inner = p.inner
+	}
+	for q := r; q != nil; q = q.Parent {
+		if q.Name == "" {
+			continue
+		}
+		q.mu.RLock()
+		m := q.syms[inner.Symbol()]
+		q.mu.RUnlock()
+		if m != nil && m.Unit() != nil && m.Unit().Equal(inner) {
+			return q.Name + ":" + u.Symbol()
+		}
+	}
+	return u.Symbol()
 }
 
 func (r *Registry) Primitive(symbol string, alias ...string) Maker {
@@ -24,11 +63,29 @@ func (r *Registry) Primitive(symbol string, alias ...string) Maker {
 	return r.register(symbol, m, alias...)
 }
 
+func (r *Registry) Dimensionless(symbol string, alias ...string) Maker {
+	m := Dimensionless(symbol)
+	return r.register(symbol, m, alias...)
+}
+
 func (r *Registry) Derive(symbol string, v Qualified, alias ...string) Maker {
 	m := Derive(symbol, v)
 	return r.register(symbol, m, alias...)
 }
 
+func (r *Registry) Affine(symbol string, delta Maker, offset float64, alias ...string) Maker {
+	m := Affine(symbol, delta, offset)
+	return r.register(symbol, m, alias...)
+}
+
+func (r *Registry) Logarithmic(symbol string, ref Qualified, decibels float64, field bool, alias ...string) Maker {
+	m := Logarithmic(symbol, ref, decibels, field)
+	return r.register(symbol, m, alias...)
+}
+
+// Register registers the named unit m under its symbol and any aliases
+// given.  Panics if m is not a named unit, if a symbol is already
+// registered, or if r is frozen; see RegisterErr.
 func (r *Registry) Register(m Maker, alias ...string) Maker {
 	if u := m.Unit(); u != nil {
 		return r.register(u.Symbol(), m, alias...)
@@ -36,55 +93,416 @@ func (r *Registry) Register(m Maker, alias ...string) Maker {
 	panic("Register can only be used for named units")
 }
 
+// RegisterErr registers the named unit m under its symbol and any aliases
+// given, as Register does, but returns an error instead of panicking if
+// m is not a named unit, if a symbol is already registered, or if r is
+// frozen (see Freeze).
+func (r *Registry) RegisterErr(m Maker, alias ...string) (Maker, error) {
+	u := m.Unit()
+	if u == nil {
+		return nil, errors.New("Register can only be used for named units")
+	}
+	if err := r.add(u.Symbol(), m, alias); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Prefix registers a new prefix named symbol with the multiplier mult,
+// under symbol and any aliases given.  Panics if a symbol is already
+// registered as a prefix, or if r is frozen; see PrefixErr.
 func (r *Registry) Prefix(symbol string, mult float64, alias ...string) func(m Maker) Maker {
+	m, err := r.PrefixErr(symbol, mult, alias...)
+	if err != nil {
+		panic(err.Error())
+	}
+	return m
+}
+
+// PrefixErr registers a new prefix as Prefix does, but returns an error
+// instead of panicking if a symbol is already registered as a prefix, or
+// if r is frozen.
+func (r *Registry) PrefixErr(symbol string, mult float64, alias ...string) (func(m Maker) Maker, error) {
 	m := Prefix(symbol, mult)
-	if r.pref == nil {
-		r.pref = make(map[string]func(Maker) Maker)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.frozen {
+		return nil, fmt.Errorf("Prefix %q: %w", symbol, Frozen)
 	}
 	for _, s := range append(alias, symbol) {
-		if r.pref[s] != nil {
-			panic(fmt.Sprintf("Prefix %q already registered", s))
+		if r.pref.get(s) != nil {
+			return nil, fmt.Errorf("Prefix %q already registered", s)
 		}
-		r.pref[s] = m
 	}
-	return m
+	for _, s := range alias {
+		r.pref.put(s, m, mult, true)
+	}
+	r.pref.put(symbol, m, mult, false)
+	return m, nil
 }
 
 func (r *Registry) register(symbol string, m Maker, alias ...string) Maker {
+	if err := r.add(symbol, m, alias); err != nil {
+		panic(err.Error())
+	}
+	return m
+}
+
+// add records m under symbol and its aliases, unless r is frozen or any
+// of them is already registered.
+func (r *Registry) add(symbol string, m Maker, alias []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.frozen {
+		return fmt.Errorf("Unit %q: %w", symbol, Frozen)
+	}
 	if r.syms == nil {
 		r.syms = make(map[string]Maker)
 	}
-	for _, s := range append(alias, symbol) {
+	names := append(alias, symbol)
+	for _, s := range names {
 		if r.syms[s] != nil {
-			panic(fmt.Sprintf("Unit %q already registered", s))
+			return fmt.Errorf("Unit %q already registered", s)
 		}
+	}
+	for _, s := range names {
 		r.syms[s] = m
 	}
-	return m
+	return nil
 }
 
-func (r *Registry) Find(n string) Maker {
+// primitive returns the unit registered in r as symbol, registering a new
+// primitive unit for it if there is none.  If r is nil, a new primitive
+// unit is returned.
+func (r *Registry) primitive(symbol string) (Maker, error) {
 	if r == nil {
-		return nil
+		return Primitive(symbol), nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Another goroutine may have registered symbol since it was looked up.
+	if m := r.syms[symbol]; m != nil {
+		return m, nil
+	}
+	if r.frozen {
+		return nil, fmt.Errorf("Unit %q: %w", symbol, Frozen)
+	}
+	if r.syms == nil {
+		r.syms = make(map[string]Maker)
+	}
+	m := Primitive(symbol)
+	r.syms[symbol] = m
+	return m, nil
+}
+
+// Freeze makes r read-only.  Afterwards, RegisterErr, PrefixErr, MergeErr
+// and Parse return errors wrapping Frozen instead of registering units in
+// r, while Register, Prefix, Merge, Primitive, Derive and the other
+// methods that register units panic.  Freezing r does not freeze its
+// Parent.
+func (r *Registry) Freeze() {
+	r.mu.Lock()
+	r.frozen = true
+	r.mu.Unlock()
+}
+
+// Ambiguous is wrapped by the errors Registry.FindErr returns for names
+// that could refer to more than one unit.
+var Ambiguous = errors.New("ambiguous unit")
+
+// Find returns the unit registered as n, or nil if there is none or n is
+// ambiguous.  See FindErr.
+func (r *Registry) Find(n string) Maker {
+	m, _ := r.FindErr(n)
+	return m
+}
+
+// FindErr returns the unit registered as n, looking first for a unit
+// registered with exactly that symbol in r and then in each of its
+// parents, nearest first.  A symbol qualified by the name of r or one of
+// its parents, as in "us:ft", is looked up in that registry.
+//
+// Failing that, n is interpreted as a single registered prefix followed by
+// a symbol registered exactly, so that "km" is found as kilo-"m", but
+// "mkm" is not found, since prefixes are not applied to prefixed units,
+// nor to units whose Info has NoPrefixes set (see Describe).
+// Prefixes registered in r shadow those with the same symbol in its
+// parents.  Prefixes are applied to long names (see Describe) only by
+// their own long names (see DescribePrefix), so that "kilometres" is found
+// but "kmetres" is not.  If n can be split in more than one way, the
+// longest prefix, wherever it is registered, is used if the
+// interpretations are equal, as with "dam" if "am" were a symbol for
+// 100 m, and otherwise n is ambiguous and an error wrapping Ambiguous is
+// returned.
+func (r *Registry) FindErr(n string) (Maker, error) {
+	if m := r.exact(n); m != nil {
+		return m, nil
+	}
+	if i := strings.IndexByte(n, ':'); i > 0 {
+		// A qualified symbol, as in "us:ft", is looked up in the named
+		// registry, which must be r or one of its parents.
+		for q := r; q != nil; q = q.Parent {
+			if q.Name == n[:i] {
+				return q.FindErr(n[i+1:])
+			}
+		}
+		return nil, fmt.Errorf("unknown unit %q", n)
+	}
+	// Matching prefixes are copied out so that the remainder can be
+	// looked up without holding the lock.
+	type split struct {
+		n    int
+		m    func(Maker) Maker
+		long bool
 	}
-	if got := r.syms[n]; got != nil {
-		return got
+	var buf [4]split
+	splits := buf[:0]
+	for q := r; q != nil; q = q.Parent {
+		q.mu.RLock()
+		q.pref.match(n, func(i int, p *prefixTrie) {
+			for _, s := range splits {
+				if s.n == i {
+					return // shadowed
+				}
+			}
+			splits = append(splits, split{i, p.m, p.long})
+		})
+		q.mu.RUnlock()
 	}
-	for prefix, m := range r.pref {
-		if strings.HasPrefix(n, prefix) {
-			if sub := r.Find(n[len(prefix):]); sub != nil {
-				return m(sub)
+	// Each registry's prefixes match longest first, but a parent's may be
+	// longer than those of r.
+	sort.SliceStable(splits, func(i, j int) bool { return splits[i].n > splits[j].n })
+	var found Maker
+	var ambiguous []string
+	first := 0
+	for _, s := range splits {
+		sub, long := r.lookup(n[s.n:])
+		if sub == nil || long != s.long {
+			continue
+		}
+		if i, _ := r.UnitInfo(sub.Unit()); i.NoPrefixes {
+			continue
+		}
+		m := s.m(sub)
+		switch {
+		case found == nil:
+			found, first = m, s.n
+		case !found(1).Equal(m(1)):
+			if ambiguous == nil {
+				ambiguous = append(ambiguous, n[:first]+"-"+n[first:])
 			}
+			ambiguous = append(ambiguous, n[:s.n]+"-"+n[s.n:])
 		}
 	}
-	return r.Parent.Find(n)
+	if ambiguous != nil {
+		return nil, fmt.Errorf("%w %q: could be %s", Ambiguous, n, strings.Join(ambiguous, " or "))
+	}
+	if found == nil {
+		return nil, fmt.Errorf("unknown unit %q", n)
+	}
+	return found, nil
+}
+
+// exact returns the unit registered with exactly the symbol n in r or its
+// parents, nearest first, or nil if there is none.
+func (r *Registry) exact(n string) Maker {
+	m, _ := r.lookup(n)
+	return m
 }
 
+// lookup returns the unit registered with exactly the symbol n in r or
+// its parents, nearest first, and whether n is its long name there.
+func (r *Registry) lookup(n string) (Maker, bool) {
+	for q := r; q != nil; q = q.Parent {
+		q.mu.RLock()
+		m, long := q.syms[n], q.names[n]
+		q.mu.RUnlock()
+		if m != nil {
+			return m, long
+		}
+	}
+	return nil, false
+}
+
+// Merge copies the units, prefixes and Info registered in p, but not its
+// parents, into r, replacing any registered under the same symbols.
+// Panics if r is frozen; see MergeErr.
 func (r *Registry) Merge(p *Registry) {
+	if err := r.MergeErr(p); err != nil {
+		panic(err.Error())
+	}
+}
+
+// MergeErr copies the units and prefixes registered in p into r as Merge
+// does, but returns an error wrapping Frozen instead of panicking if r is
+// frozen.
+func (r *Registry) MergeErr(p *Registry) error {
+	if r == p {
+		return nil
+	}
+	p.mu.RLock()
+	syms := make(map[string]Maker, len(p.syms))
 	for s, m := range p.syms {
+		syms[s] = m
+	}
+	names := make(map[string]bool, len(p.names))
+	for s := range p.names {
+		names[s] = true
+	}
+	infos := make(map[Unit]Info, len(p.infos))
+	for u, i := range p.infos {
+		infos[u] = i
+	}
+	type entry struct {
+		symbol string
+		p      prefixTrie
+	}
+	var pref []entry
+	p.pref.walk(func(s string, e *prefixTrie) {
+		pref = append(pref, entry{s, *e})
+	})
+	p.mu.RUnlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.frozen {
+		return fmt.Errorf("Merge: %w", Frozen)
+	}
+	if r.syms == nil {
+		r.syms = make(map[string]Maker)
+	}
+	for s, m := range syms {
 		r.syms[s] = m
+		delete(r.names, s)
+	}
+	for s := range names {
+		r.markName(s)
+	}
+	for u, i := range infos {
+		r.describe(u, i)
+	}
+	for _, e := range pref {
+		p := r.pref.put(e.symbol, e.p.m, e.p.mult, e.p.alias)
+		p.long, p.name = e.p.long, e.p.name
 	}
-	for s, m := range p.pref {
-		r.pref[s] = m
+	return nil
+}
+
+// RegisteredUnit describes a symbol registered for a unit in a Registry.
+type RegisteredUnit struct {
+	Symbol   string    // the symbol or alias registered
+	Unit     Maker     // the unit registered
+	Alias    bool      // whether Symbol is an alias for the unit's symbol
+	Registry *Registry // the registry Symbol is registered in
+}
+
+// RegisteredPrefix describes a prefix registered in a Registry.
+type RegisteredPrefix struct {
+	Symbol   string            // the symbol or alias registered
+	Mult     float64           // the prefix's multiplier
+	Prefix   func(Maker) Maker // the prefix
+	Alias    bool              // whether Symbol is an alias for the prefix's symbol
+	Registry *Registry         // the registry Symbol is registered in
+}
+
+// Units returns the symbols and aliases registered for units in r and its
+// parents, nearest first, and sorted by symbol within each registry.
+// Symbols shadowed by those registered in a nearer registry are omitted,
+// so that Find returns each unit listed for its symbol.
+func (r *Registry) Units() []RegisteredUnit {
+	var list []RegisteredUnit
+	seen := make(map[string]bool)
+	for q := r; q != nil; q = q.Parent {
+		start := len(list)
+		q.mu.RLock()
+		for s, m := range q.syms {
+			if !seen[s] {
+				list = append(list, RegisteredUnit{s, m, s != m.Unit().Symbol(), q})
+			}
+		}
+		q.mu.RUnlock()
+		added := list[start:]
+		sort.Slice(added, func(i, j int) bool { return added[i].Symbol < added[j].Symbol })
+		for _, u := range added {
+			seen[u.Symbol] = true
+		}
+	}
+	return list
+}
+
+// Prefixes returns the prefixes registered in r and its parents, as
+// described in Units.
+func (r *Registry) Prefixes() []RegisteredPrefix {
+	var list []RegisteredPrefix
+	seen := make(map[string]bool)
+	for q := r; q != nil; q = q.Parent {
+		start := len(list)
+		q.mu.RLock()
+		q.pref.walk(func(s string, p *prefixTrie) {
+			if !seen[s] {
+				list = append(list, RegisteredPrefix{s, p.mult, p.m, p.alias, q})
+			}
+		})
+		q.mu.RUnlock()
+		added := list[start:]
+		sort.Slice(added, func(i, j int) bool { return added[i].Symbol < added[j].Symbol })
+		for _, p := range added {
+			seen[p.Symbol] = true
+		}
+	}
+	return list
+}
+
+// Conversion is a value expressed in a registered unit.
+type Conversion struct {
+	Unit  RegisteredUnit
+	Value Value
+
+	// OtherKind is true if Unit is of a different kind than the value
+	// converted (see Kind), in which case Value is that value
+	// reinterpreted as Unit's kind (see Value.As).
+	OtherKind bool
+}
+
+// Conformable returns v converted to each unit registered in r and its
+// parents that it can be converted to, in the order given by Units, like
+// the "?" command of GNU Units.  Aliases are omitted, and each unit is
+// listed once, under the symbol it is first found with.
+//
+// Units of a different kind than v (see Kind), which v cannot be
+// converted to directly, are listed with OtherKind set, so that for 5 Hz,
+// 5 Bq is listed as well.  Prefixed units, such as km for m, are not
+// listed; to list them, apply the prefixes returned by Prefixes that are
+// not aliases to the units listed.
+func (r *Registry) Conformable(v Qualified) []Conversion {
+	a := FromQualified(v)
+	var list []Conversion
+	var seen []Unit
+outer:
+	for _, ru := range r.Units() {
+		if ru.Alias {
+			continue
+		}
+		u := ru.Unit.Unit()
+		for _, s := range seen {
+			if identical(s, u) {
+				continue outer
+			}
+		}
+		seen = append(seen, u)
+		if !a.U.Equiv(ru.Unit.Units()) {
+			continue
+		}
+		b, other := a, !kindsAgree(a.U, ru.Unit.Units())
+		if other {
+			var ok bool
+			if b, ok = a.As(ru.Unit.Units().Kind()); !ok {
+				continue
+			}
+		}
+		if c, remain := b.Convert(ru.Unit); remain.Empty() {
+			list = append(list, Conversion{ru, c, other})
+		}
 	}
+	return list
 }
@@ -0,0 +1,311 @@
+package unit_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dnesting/unit"
+)
+
+func TestFreeze(t *testing.T) {
+	r := unit.NewRegistry("frozen", nil)
+	m := r.Primitive("m")
+	k := r.Prefix("k", 1000)
+	r.Freeze()
+
+	if got := r.Find("km"); got == nil || !got(1).Equal(k(m)(1)) {
+		t.Errorf("km should still be found, got %v", got)
+	}
+	if _, err := r.RegisterErr(unit.Primitive("s")); !errors.Is(err, unit.Frozen) {
+		t.Errorf("RegisterErr should fail with Frozen, got %v", err)
+	}
+	if v, err := unit.Parse("2 m", r, false); err != nil || !v.Equal(m(2)) {
+		t.Errorf("Parse should find registered units, got %v (err=%v)", v, err)
+	}
+	if _, err := unit.Parse("2 furlong", r, false); !errors.Is(err, unit.Frozen) {
+		t.Errorf("Parse should fail to register units with Frozen, got %v", err)
+	}
+	if r.Find("furlong") != nil {
+		t.Errorf("furlong should not have been registered")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Derive should panic once frozen")
+			}
+		}()
+		r.Derive("ft", m(0.3048))
+	}()
+
+	if _, err := r.PrefixErr("M", 1e6); !errors.Is(err, unit.Frozen) {
+		t.Errorf("PrefixErr should fail with Frozen, got %v", err)
+	}
+	if err := r.MergeErr(unit.NewRegistry("", nil)); !errors.Is(err, unit.Frozen) {
+		t.Errorf("MergeErr should fail with Frozen, got %v", err)
+	}
+
+	// The parent of a frozen registry is unaffected.
+	parent := unit.NewRegistry("parent", nil)
+	child := unit.NewRegistry("child", parent)
+	child.Freeze()
+	if _, err := parent.RegisterErr(unit.Primitive("s")); err != nil {
+		t.Errorf("RegisterErr should succeed in the parent, got %v", err)
+	}
+	if _, err := parent.PrefixErr("k", 1000); err != nil {
+		t.Errorf("PrefixErr should succeed in the parent, got %v", err)
+	}
+	if _, err := child.RegisterErr(unit.Primitive("g")); !errors.Is(err, unit.Frozen) {
+		t.Errorf("RegisterErr should fail with Frozen in the child, got %v", err)
+	}
+	if got := child.Find("ks"); got == nil {
+		t.Errorf("ks should be found through the parent")
+	}
+}
+
+func TestRegisterErr(t *testing.T) {
+	r := unit.NewRegistry("", nil)
+	m, err := r.RegisterErr(unit.Primitive("m"), "metre")
+	if err != nil || r.Find("metre") == nil {
+		t.Fatalf("RegisterErr should register m and metre, got %v", err)
+	}
+	if _, err := r.RegisterErr(unit.Primitive("metre")); err == nil {
+		t.Errorf("registering metre twice should fail")
+	}
+	if _, err := r.RegisterErr(m.Mul(m)); err == nil {
+		t.Errorf("registering m^2 should fail")
+	}
+	if _, err := r.PrefixErr("k", 1000); err != nil {
+		t.Errorf("PrefixErr should register k, got %v", err)
+	}
+	if _, err := r.PrefixErr("k", 1000); err == nil {
+		t.Errorf("registering k twice should fail")
+	}
+}
+
+// TestRegistryConcurrent is most useful run with the race detector, as
+// with "go test -race".
+func TestRegistryConcurrent(t *testing.T) {
+	r := unit.NewRegistry("busy", nil)
+	m := r.Primitive("m")
+	r.Prefix("k", 1000)
+	child := unit.NewRegistry("child", r)
+
+	const workers = 8
+	var wg sync.WaitGroup
+	found := make([][]unit.Unit, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				if got := child.Find("km"); got == nil || !got(1).Equal(m(1000)) {
+					t.Errorf("km should be found, got %v", got)
+					return
+				}
+				// Every worker parses the same new units, which must be
+				// registered only once.
+				name := fmt.Sprintf("u%d", j%20)
+				v, err := unit.Parse("3 k"+name+"/"+name, r, false)
+				if err != nil {
+					t.Errorf("Parse: %v", err)
+					return
+				}
+				if _, err := unit.Parse("2 "+name, child, false); err != nil {
+					t.Errorf("Parse: %v", err)
+					return
+				}
+				found[i] = append(found[i], v.U.D[0])
+			}
+		}(i)
+	}
+	wg.Wait()
+	for i := 1; i < workers; i++ {
+		for j := range found[i] {
+			if found[i][j] != found[0][j] {
+				t.Fatalf("workers registered different units for %v", found[i][j].Symbol())
+			}
+		}
+	}
+}
+
+func TestFindPrefix(t *testing.T) {
+	base := unit.NewRegistry("base", nil)
+	m := base.Primitive("m")
+	min := base.Primitive("min")
+	base.Prefix("k", 1000)
+	deci := base.Prefix("d", 0.1)
+	base.Prefix("da", 10)
+	r := unit.NewRegistry("", base)
+	in := r.Derive("in", m(0.0254))
+	kibi := r.Prefix("k", 1024)
+	milli := r.Prefix("m", 0.001)
+	hm := r.Derive("hm", m(100))
+	r.Derive("am", m(1))
+
+	for _, x := range []struct {
+		name     string
+		expected unit.Value
+	}{
+		{"m", m(1)},
+		{"min", min(1)},        // exact symbols in a parent beat prefixes
+		{"km", kibi(m)(1)},     // r's prefixes shadow its parent's
+		{"mm", milli(m)(1)},    //
+		{"kin", kibi(in)(1)},   //
+		{"kmin", kibi(min)(1)}, // prefixes apply to parents' units
+		{"dhm", deci(hm)(1)},   // and parents' prefixes to r's units
+		{"mkm", unit.Value{}},  // prefixes do not stack
+		{"k", unit.Value{}},    // prefixes alone are not units
+		{"dam", unit.Value{}},  // "da m" or "d am"
+	} {
+		for i := 0; i < 20; i++ {
+			got, err := r.FindErr(x.name)
+			if x.expected.U.Empty() {
+				if got != nil || err == nil {
+					t.Errorf("%q should not be found, got %v", x.name, got)
+				}
+				break
+			}
+			if err != nil || !got(1).Equal(x.expected) || !got(1).U.Equal(x.expected.U) {
+				t.Errorf("%q should give %v, got %v (err=%v)", x.name, x.expected, got, err)
+				break
+			}
+		}
+	}
+
+	_, err := r.FindErr("dam")
+	if !errors.Is(err, unit.Ambiguous) {
+		t.Errorf("dam should be ambiguous, got %v", err)
+	}
+	if _, err := unit.Parse("3 dam", r, false); !errors.Is(err, unit.Ambiguous) {
+		t.Errorf("Parse should reject ambiguous units, got %v", err)
+	}
+
+	// Splits giving equal units are not ambiguous, and the longest prefix
+	// is used.
+	eq := unit.NewRegistry("", nil)
+	em := eq.Primitive("m")
+	eq.Prefix("da", 10)
+	eq.Prefix("d", 0.1)
+	eq.Derive("am", em(100))
+	if got, err := eq.FindErr("dam"); err != nil || got.Unit().Deriv().String() != "10 m" {
+		t.Errorf("dam should give da-m, got %v (err=%v)", got, err)
+	}
+	// The same holds if the shorter prefix is registered in a child.
+	child := unit.NewRegistry("", eq)
+	child.Prefix("d", 0.1)
+	if got, err := child.FindErr("dam"); err != nil || got.Unit().Deriv().String() != "10 m" {
+		t.Errorf("dam should give da-m through the parent, got %v (err=%v)", got, err)
+	}
+}
+
+func TestEnumerate(t *testing.T) {
+	base := unit.NewRegistry("base", nil)
+	m := base.Primitive("m", "metre")
+	base.Primitive("s")
+	base.Prefix("k", 1000)
+	base.Prefix("m", 0.001, "milli")
+	r := unit.NewRegistry("r", base)
+	r.Derive("ft", m(0.3048), "foot")
+	r.Derive("s", m(1)) // shadows base's s
+	r.Prefix("k", 1024)
+
+	var units []string
+	for _, u := range r.Units() {
+		s := u.Registry.Name + ":" + u.Symbol
+		if u.Alias {
+			s += "=" + u.Unit.Unit().Symbol()
+		}
+		units = append(units, s)
+	}
+	if got, want := strings.Join(units, " "), "r:foot=ft r:ft r:s base:m base:metre=m"; got != want {
+		t.Errorf("expected units %q, got %q", want, got)
+	}
+
+	var prefixes []string
+	for _, p := range r.Prefixes() {
+		s := fmt.Sprintf("%s:%s=%g", p.Registry.Name, p.Symbol, p.Mult)
+		if p.Alias {
+			s += " (alias)"
+		}
+		prefixes = append(prefixes, s)
+	}
+	if got, want := strings.Join(prefixes, " "), "r:k=1024 base:m=0.001 base:milli=0.001 (alias)"; got != want {
+		t.Errorf("expected prefixes %q, got %q", want, got)
+	}
+}
+
+func TestConformable(t *testing.T) {
+	r := unit.NewRegistry("", nil)
+	m := r.Primitive("m", "metre")
+	ft := r.Derive("ft", m(0.3048))
+	r.Primitive("kg")
+	s := r.Primitive("s")
+	hz := r.Register(unit.NewKind("frequency", nil).Derive("Hz", unit.Scalar(1).Div(s)))
+	r.Register(unit.NewKind("activity", nil).Derive("Bq", unit.Scalar(1).Div(s)))
+	child := unit.NewRegistry("", r)
+	child.Register(ft) // listed once
+
+	var got []string
+	for _, c := range child.Conformable(ft(1)) {
+		got = append(got, c.Value.String())
+	}
+	if want := "1 ft 0.3048 m"; strings.Join(got, " ") != want {
+		t.Errorf("expected %q, got %q", want, strings.Join(got, " "))
+	}
+	got = nil
+	for _, c := range r.Conformable(hz(5)) {
+		s := c.Value.String()
+		if c.OtherKind {
+			s += " (" + c.Value.Kind().String() + ")"
+		}
+		got = append(got, s)
+	}
+	if want := "5 Bq (activity) 5 Hz"; strings.Join(got, " ") != want {
+		t.Errorf("expected %q, got %q", want, strings.Join(got, " "))
+	}
+}
+
+func ExampleRegistry_Conformable() {
+	r := unit.NewRegistry("", nil)
+	m := r.Primitive("m")
+	r.Derive("ft", m(0.3048))
+	r.Derive("in", m(0.0254))
+	r.Primitive("s")
+
+	for _, c := range r.Conformable(m(1)) {
+		fmt.Printf("%s\t%.4f\n", c.Unit.Symbol, c.Value.S)
+	}
+	// Output:
+	// ft	3.2808
+	// in	39.3701
+	// m	1.0000
+}
+
+func ExampleRegistry_FindErr() {
+	r := unit.NewRegistry("", nil)
+	m := r.Primitive("m")
+	r.Derive("am", m(1))
+	r.Prefix("d", 0.1)
+	r.Prefix("da", 10)
+
+	_, err := r.FindErr("dam")
+	fmt.Println(err)
+	// Output:
+	// ambiguous unit "dam": could be da-m or d-am
+}
+
+func ExampleRegistry_Freeze() {
+	r := unit.NewRegistry("", nil)
+	r.Primitive("m")
+	r.Freeze()
+
+	_, err := unit.Parse("3 ft", r, false)
+	fmt.Println(err)
+	fmt.Println(errors.Is(err, unit.Frozen))
+	// Output:
+	// units parse: Unit "ft": registry is frozen
+	// true
+}
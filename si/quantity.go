@@ -0,0 +1,204 @@
+package si
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/dnesting/unit"
+)
+
+// Dimension identifies the physical dimension of a Quantity.  It is
+// implemented by empty marker types such as Length and Time, whose Unit
+// method returns the coherent SI unit in which quantities of that
+// dimension are stored.
+type Dimension interface {
+	Unit() unit.Maker
+}
+
+// Dimensions of the SI base quantities.
+type (
+	Length      struct{}
+	Mass        struct{}
+	Time        struct{}
+	Current     struct{}
+	Temperature struct{}
+	Amount      struct{}
+	Luminosity  struct{}
+)
+
+// Dimensions of common derived quantities.
+type (
+	Area         struct{}
+	Volume       struct{}
+	Velocity     struct{}
+	Acceleration struct{}
+	Force        struct{}
+	Energy       struct{}
+	Power        struct{}
+	Pressure     struct{}
+	Frequency    struct{}
+	Charge       struct{}
+	Voltage      struct{}
+	Resistance   struct{}
+	Density      struct{}
+)
+
+func (Length) Unit() unit.Maker      { return Metre }
+func (Mass) Unit() unit.Maker        { return Kilogram }
+func (Time) Unit() unit.Maker        { return Second }
+func (Current) Unit() unit.Maker     { return Ampere }
+func (Temperature) Unit() unit.Maker { return Kelvin }
+func (Amount) Unit() unit.Maker      { return Mole }
+func (Luminosity) Unit() unit.Maker  { return Candela }
+
+func (Area) Unit() unit.Maker         { return Metre.Pow(2) }
+func (Volume) Unit() unit.Maker       { return Metre.Pow(3) }
+func (Velocity) Unit() unit.Maker     { return Metre.Div(Second) }
+func (Acceleration) Unit() unit.Maker { return Metre.Div(Second.Pow(2)) }
+func (Force) Unit() unit.Maker        { return Newton }
+func (Energy) Unit() unit.Maker       { return Joule }
+func (Power) Unit() unit.Maker        { return Watt }
+func (Pressure) Unit() unit.Maker     { return Pascal }
+func (Frequency) Unit() unit.Maker    { return Hertz }
+func (Charge) Unit() unit.Maker       { return Coulomb }
+func (Voltage) Unit() unit.Maker      { return Volt }
+func (Resistance) Unit() unit.Maker   { return Ohm }
+func (Density) Unit() unit.Maker      { return Kilogram.Div(Metre.Pow(3)) }
+
+// Quantity is a value whose dimension D is checked at compile time, so
+// that adding a Quantity[Length] to a Quantity[Time] does not compile.
+// Its scalar is stored in D's coherent SI unit.  Use Value and From to
+// move between Quantity and the dynamically checked unit.Value.
+type Quantity[D Dimension] struct {
+	v float64
+}
+
+// Of returns a Quantity of v, expressed in D's SI unit, so that
+// Of[Length](3) is 3 m.
+func Of[D Dimension](v float64) Quantity[D] {
+	return Quantity[D]{v}
+}
+
+// From converts v into a Quantity of dimension D.  Returns false if v's
+// units are not conformable with D's SI unit.
+func From[D Dimension](v unit.Qualified) (Quantity[D], bool) {
+	var d D
+	r, remain := unit.FromQualified(v).Convert(d.Unit())
+	if !remain.Empty() {
+		return Quantity[D]{}, false
+	}
+	return Quantity[D]{r.S}, true
+}
+
+// SI returns q's scalar, in D's SI unit.
+func (q Quantity[D]) SI() float64 { return q.v }
+
+// Value returns q as a unit.Value in D's SI unit.
+func (q Quantity[D]) Value() unit.Value {
+	var d D
+	return d.Unit()(q.v)
+}
+
+// In returns q converted into the units of m, which must be conformable
+// with D.  Returns false if they are not.
+func (q Quantity[D]) In(m unit.Maker) (unit.Value, bool) {
+	r, remain := q.Value().Convert(m)
+	return r, remain.Empty()
+}
+
+func (q Quantity[D]) String() string { return q.Value().String() }
+
+// Add returns q + o.
+func (q Quantity[D]) Add(o Quantity[D]) Quantity[D] { return Quantity[D]{q.v + o.v} }
+
+// Sub returns q - o.
+func (q Quantity[D]) Sub(o Quantity[D]) Quantity[D] { return Quantity[D]{q.v - o.v} }
+
+// MulN returns q scaled by n.
+func (q Quantity[D]) MulN(n float64) Quantity[D] { return Quantity[D]{q.v * n} }
+
+// DivN returns q divided by n.
+func (q Quantity[D]) DivN(n float64) Quantity[D] { return Quantity[D]{q.v / n} }
+
+// Less returns true if q is less than o.
+func (q Quantity[D]) Less(o Quantity[D]) bool { return q.v < o.v }
+
+// Relation describes the dimensional relationship A × B = C, allowing
+// quantities of those dimensions to be multiplied and divided with the
+// result type checked at compile time.  The SI units of the dimensions
+// must satisfy the same relationship, so relations should be created with
+// NewRelation, which checks that they do.
+type Relation[A, B, C Dimension] struct{}
+
+// NewRelation returns the Relation A × B = C.  Panics if the product of
+// the SI units of A and B is not exactly the SI unit of C, so that a
+// mistaken relation is caught when it is declared rather than giving
+// wrong results.
+func NewRelation[A, B, C Dimension]() Relation[A, B, C] {
+	var r Relation[A, B, C]
+	if err := r.check(); err != nil {
+		panic(err.Error())
+	}
+	return r
+}
+
+// check returns an error if the SI units of A and B do not multiply to
+// exactly the SI unit of C.
+func (Relation[A, B, C]) check() error {
+	var a A
+	var b B
+	var c C
+	r, remain := a.Unit().Mul(b.Unit())(1).Convert(c.Unit())
+	if !remain.Empty() || math.Abs(r.S-1) > 1e-12 {
+		return fmt.Errorf("si.Relation: %v × %v is not %v", a.Unit(), b.Unit(), c.Unit())
+	}
+	return nil
+}
+
+// Mul returns a × b.
+func (Relation[A, B, C]) Mul(a Quantity[A], b Quantity[B]) Quantity[C] {
+	return Quantity[C]{a.v * b.v}
+}
+
+// Div returns c / b.
+func (Relation[A, B, C]) Div(c Quantity[C], b Quantity[B]) Quantity[A] {
+	return Quantity[A]{c.v / b.v}
+}
+
+// DivA returns c / a.
+func (Relation[A, B, C]) DivA(c Quantity[C], a Quantity[A]) Quantity[B] {
+	return Quantity[B]{c.v / a.v}
+}
+
+// Relations between common dimensions.  For instance, a velocity is
+// VelocityTime.Div(distance, time), and a force is
+// MassAcceleration.Mul(mass, acceleration).
+var (
+	LengthLength      Relation[Length, Length, Area]
+	AreaLength        Relation[Area, Length, Volume]
+	VelocityTime      Relation[Velocity, Time, Length]
+	AccelerationTime  Relation[Acceleration, Time, Velocity]
+	MassAcceleration  Relation[Mass, Acceleration, Force]
+	ForceLength       Relation[Force, Length, Energy]
+	PowerTime         Relation[Power, Time, Energy]
+	PressureArea      Relation[Pressure, Area, Force]
+	DensityVolume     Relation[Density, Volume, Mass]
+	CurrentTime       Relation[Current, Time, Charge]
+	VoltageCurrent    Relation[Voltage, Current, Power]
+	ResistanceCurrent Relation[Resistance, Current, Voltage]
+)
+
+// The relations above are declared as plain variables, since the units
+// they depend on may not be initialized yet when they are, and are
+// checked here instead.
+func init() {
+	for _, r := range []interface{ check() error }{
+		LengthLength, AreaLength, VelocityTime, AccelerationTime,
+		MassAcceleration, ForceLength, PowerTime, PressureArea,
+		DensityVolume, CurrentTime, VoltageCurrent, ResistanceCurrent,
+	} {
+		if err := r.check(); err != nil {
+			panic(err.Error())
+		}
+	}
+}
@@ -3,13 +3,19 @@
 package si
 
 import (
+	"math"
+	"math/big"
 	"time"
 
 	"github.com/dnesting/unit"
 	"github.com/dnesting/unit/natural"
 )
 
-var Registry unit.Registry
+var Registry = unit.Registry{Name: "si"}
+
+// Chem holds units whose symbols would otherwise collide with prefixed SI
+// units, such as pH.  Its parent is Registry.
+var Chem = unit.NewRegistry("chem", &Registry)
 
 var (
 	Yotta = Registry.Prefix("Y", 1e24)
@@ -38,26 +44,26 @@ var (
 	// transition between the two hyperfine ground states of caesium,
 	// which has a frequency, ΔνCs (natural.Caesium), of exactly
 	// 9192631770 Hz.
-	Hertz   = Registry.Derive("Hz", natural.Caesium(1.0/9192631770))
+	Hertz   = Registry.Register(KindFrequency.Derive("Hz", natural.Caesium.MakeRat(big.NewRat(1, 9192631770))))
 	Second  = Registry.Derive("s", Hertz(1).Recip())
 	Caesium = unit.MustConvert(natural.Caesium, Hertz)
 
 	// Metre is defined to be 1/299 792 458 of the distance the
 	// speed of light (natural.C) travels in 1 second.
-	Metre = Registry.Derive("m", natural.C.Mul(Second)(1.0/299792458))
+	Metre = Registry.Derive("m", natural.C.Mul(Second).MakeRat(big.NewRat(1, 299792458)))
 	Meter = Metre
 	C     = unit.MustConvert(natural.C, Metre.Div(Second))
 
 	// Gram is derived from the Planck constant (h), which is defined
 	// to be 6.62607015×10−34 kg m^2/s.
-	Gram     = Registry.Derive("g", natural.H.Mul(Second).Div(Metre.Pow(2))(1.0/6.62607015e-38))
+	Gram     = Registry.Derive("g", natural.H.Mul(Second).Div(Metre.Pow(2)).MakeRat(inv("6.62607015e-38")))
 	Kilogram = Kilo(Gram)
 	Newton   = Registry.Derive("N", Kilogram.Mul(Metre).Div(Second.Pow(2)))
-	Joule    = Registry.Derive("J", Metre.Mul(Newton))
+	Joule    = Registry.Register(KindEnergy.Derive("J", Metre.Mul(Newton)))
 	H        = unit.MustConvert(natural.H, Joule.Mul(Second))
 
 	// Ampere is derived from the elementary charge e, defined to be 1.602176634×10^−19 A s.
-	Ampere  = Registry.Derive("A", natural.E.Div(Second)(1.0/1.602176634e-19))
+	Ampere  = Registry.Derive("A", natural.E.Div(Second).MakeRat(inv("1.602176634e-19")))
 	Coulomb = Registry.Derive("C", Ampere.Mul(Second))
 	E       = unit.MustConvert(natural.E, Coulomb)
 
@@ -68,17 +74,22 @@ var (
 	Mole = Registry.Primitive("mol")
 	NA   = unit.Scalar(6.02214076e23).Div(Mole)(1)
 
-	Watt      = Registry.Derive("W", Joule.Div(Second))
-	Steradian = Registry.Derive("sr", Metre.Pow(2).Div(Metre.Pow(2)))
+	Watt = Registry.Derive("W", Joule.Div(Second))
+
+	// Radian and Steradian measure plane and solid angles.  Though they
+	// are ratios of lengths and areas, they are kept as dimensionless units
+	// (see unit.Dimensionless), so that "rad/s" is not confused with "Hz".
+	Radian    = Registry.Dimensionless("rad")
+	Steradian = Registry.Dimensionless("sr")
 
 	// Candela is derived from the luminous efficacy of monochromatic radiation of frequency
 	// 540×10^12 Hz, Kcd, defined to be 683 cd sr/W.
-	Candela = Registry.Derive("cd", natural.Kcd.Mul(Watt).Div(Steradian)(1.0/683))
+	Candela = Registry.Derive("cd", natural.Kcd.Mul(Watt).Div(Steradian).MakeRat(big.NewRat(1, 683)))
 	Kcd     = unit.MustConvert(natural.Kcd, Candela.Mul(Steradian).Div(Watt))
 
-	Becquerel = Registry.Derive("Bq", unit.Scalar(1).Div(Second))
+	Becquerel = Registry.Register(KindActivity.Derive("Bq", unit.Scalar(1).Div(Second)))
 	Farad     = Registry.Derive("F", Coulomb.Div(Volt))
-	Gray      = Registry.Derive("Gy", Joule.Div(Kilogram))
+	Gray      = Registry.Register(KindAbsorbedDose.Derive("Gy", Joule.Div(Kilogram)))
 	Henry     = Registry.Derive("H", Volt.Mul(Second).Div(Ampere))
 	Katal     = Registry.Derive("kat", Mole.Div(Second))
 	Liter     = Registry.Derive("L", Centi(Metre).Pow(3)(1000))
@@ -86,20 +97,134 @@ var (
 	Lux       = Registry.Derive("lx", Lumen.Div(Metre.Pow(2)))
 	Ohm       = Registry.Derive("Ω", Volt.Div(Ampere), "Ohm")
 	Pascal    = Registry.Derive("Pa", Newton.Div(Metre.Pow(2)))
-	Radian    = Registry.Derive("rad", Metre.Div(Metre))
 	Siemens   = Registry.Derive("S", Ampere.Div(Volt))
-	Sievert   = Registry.Derive("Sv", Joule.Div(Kilogram))
+	Sievert   = Registry.Register(KindDoseEquivalent.Derive("Sv", Joule.Div(Kilogram)))
 	Tesla     = Registry.Derive("T", Volt.Mul(Second).Div(Metre.Pow(2)))
 	Volt      = Registry.Derive("V", Watt.Div(Ampere))
 	Weber     = Registry.Derive("Wb", Joule.Div(Ampere))
 
-	// DegCelsius shares the same underlying unit as Kelvin, but on a
-	// different scale.  No conversions are therefore needed for values
-	// that represent changes in temperature.  To convert between values
-	// on their respective scales, use CToK and KToC.
-	DegCelsius = Registry.Derive("°C", Kelvin, "℃", "degC")
+	// Decibel, Bel and Neper are logarithmic gains (ratios).  A
+	// decibel describes a power ratio of 10^(1/10), and a neper a field
+	// ratio of e.
+	Decibel = Registry.Logarithmic("dB", unit.Unity, 1, false)
+	Bel     = Registry.Logarithmic("B", unit.Unity, 10, false)
+	Neper   = Registry.Logarithmic("Np", unit.Unity, 20/math.Ln10, true)
+
+	// DBm, DBW, DBV and DBSPL are logarithmic levels relative to 1 mW,
+	// 1 W, 1 V and 20 µPa respectively.  Convert moves between them and
+	// their linear equivalents.
+	DBm   = Registry.Logarithmic("dBm", Milli(Watt)(1), 1, false)
+	DBW   = Registry.Logarithmic("dBW", Watt(1), 1, false)
+	DBV   = Registry.Logarithmic("dBV", Volt(1), 1, true)
+	DBSPL = Registry.Logarithmic("dBSPL", Micro(Pascal)(20), 1, true)
+
+	// PH is the acidity of a solution: the negative decimal logarithm of
+	// its hydrogen ion activity, relative to 1 mol/L.  It is registered
+	// in Chem rather than Registry, since its symbol would hide the
+	// picohenry.
+	PH = Chem.Logarithmic("pH", Mole.Div(Liter)(1), -10, false)
+
+	// DeltaCelsius is a difference of one degree on the Celsius scale,
+	// equal to 1 K.
+	DeltaCelsius = Registry.Derive("Δ°C", Kelvin, "ΔdegC")
+	// DegCelsius is an absolute reading on the Celsius scale, whose zero
+	// is 273.15 K.  Convert applies the offset, so 0 °C converts to
+	// 273.15 K, and the difference between two DegCelsius values is
+	// expressed in DeltaCelsius.
+	DegCelsius = Registry.Affine("°C", DeltaCelsius, 273.15, "℃", "degC")
 )
 
+// Kinds of quantity that share primitive units with others, and so
+// must be told apart explicitly (see unit.Kind).  Hz and Bq both reduce to
+// 1/s, Gy and Sv to J/kg, and torque (N·m) to J.  Values of one kind cannot
+// be converted to another without first using unit.Value.As.
+var (
+	KindFrequency      = unit.NewKind("frequency", nil)
+	KindActivity       = unit.NewKind("activity", nil)
+	KindAbsorbedDose   = unit.NewKind("absorbed dose", nil)
+	KindDoseEquivalent = unit.NewKind("dose equivalent", nil)
+	KindEnergy         = unit.NewKind("energy", nil)
+	KindTorque         = unit.NewKind("torque", nil)
+)
+
+// NewtonMetre is the unit of torque, of kind KindTorque, so that a torque
+// cannot be converted to J, or an energy to NewtonMetre, without first
+// using unit.Value.As.  It is not registered, since "N m" parses as a
+// product of newtons and metres, which converts freely to either.
+var NewtonMetre = KindTorque.Derive("N·m", Newton.Mul(Metre))
+
+// Preferred lists the SI base units followed by the coherent derived units
+// with special names, for use with unit.Value.Simplify and
+// unit.WithSimplify.  J is not used for torques, so that they are not
+// rewritten as joules.  Hz, Gy and other units whose kind must be given
+// explicitly are left out.
+var Preferred = []unit.Maker{
+	Metre, Kilogram, Second, Ampere, Kelvin, Mole, Candela, Radian, Steradian,
+	Newton, Joule, Watt, Pascal, Coulomb, Volt, Ohm, Siemens, Farad, Weber,
+	Tesla, Henry, Lumen, Lux, Katal,
+}
+
+// Long names and descriptions of the prefixes and units above, for
+// unit.WithLongNames and for parsing names such as "5 kilometres".
+func init() {
+	for _, p := range [][2]string{
+		{"Y", "yotta"}, {"Z", "zetta"}, {"E", "exa"}, {"P", "peta"},
+		{"T", "tera"}, {"G", "giga"}, {"M", "mega"}, {"k", "kilo"},
+		{"h", "hecto"}, {"da", "deca"}, {"d", "deci"}, {"c", "centi"},
+		{"m", "milli"}, {"µ", "micro"}, {"n", "nano"}, {"p", "pico"},
+		{"f", "femto"}, {"a", "atto"}, {"z", "zepto"}, {"y", "yocto"},
+	} {
+		Registry.DescribePrefix(p[0], p[1])
+	}
+	for _, d := range []struct {
+		m    unit.Maker
+		info unit.Info
+	}{
+		{Second, unit.Info{Name: "second", Description: "base unit of time"}},
+		{Metre, unit.Info{Name: "metre", Description: "base unit of length"}},
+		{Gram, unit.Info{Name: "gram", Description: "unit of mass; the kilogram is the base unit"}},
+		{Ampere, unit.Info{Name: "ampere", Description: "base unit of electric current"}},
+		{Kelvin, unit.Info{Name: "kelvin", Description: "base unit of thermodynamic temperature"}},
+		{Mole, unit.Info{Name: "mole", Description: "base unit of amount of substance"}},
+		{Candela, unit.Info{Name: "candela", Description: "base unit of luminous intensity"}},
+		{Radian, unit.Info{Name: "radian", Description: "plane angle"}},
+		{Steradian, unit.Info{Name: "steradian", Description: "solid angle"}},
+		{Hertz, unit.Info{Name: "hertz", Plural: "hertz", Description: "frequency"}},
+		{Newton, unit.Info{Name: "newton", Description: "force"}},
+		{Joule, unit.Info{Name: "joule", Description: "energy"}},
+		{Watt, unit.Info{Name: "watt", Description: "power"}},
+		{Pascal, unit.Info{Name: "pascal", Description: "pressure"}},
+		{Coulomb, unit.Info{Name: "coulomb", Description: "electric charge"}},
+		{Volt, unit.Info{Name: "volt", Description: "electric potential difference"}},
+		{Ohm, unit.Info{Name: "ohm", Description: "electrical resistance"}},
+		{Siemens, unit.Info{Name: "siemens", Plural: "siemens", Description: "electrical conductance"}},
+		{Farad, unit.Info{Name: "farad", Description: "capacitance"}},
+		{Weber, unit.Info{Name: "weber", Description: "magnetic flux"}},
+		{Tesla, unit.Info{Name: "tesla", Description: "magnetic flux density"}},
+		{Henry, unit.Info{Name: "henry", Plural: "henries", Description: "inductance"}},
+		{Lumen, unit.Info{Name: "lumen", Description: "luminous flux"}},
+		{Lux, unit.Info{Name: "lux", Plural: "lux", Description: "illuminance"}},
+		{Becquerel, unit.Info{Name: "becquerel", Description: "radioactive activity"}},
+		{Gray, unit.Info{Name: "gray", Description: "absorbed dose of ionizing radiation"}},
+		{Sievert, unit.Info{Name: "sievert", Description: "equivalent dose of ionizing radiation"}},
+		{Katal, unit.Info{Name: "katal", Description: "catalytic activity"}},
+		{Liter, unit.Info{Name: "litre", Description: "volume; accepted for use with the SI"}},
+		{DegCelsius, unit.Info{Name: "degree Celsius", Plural: "degrees Celsius", Description: "temperature on the Celsius scale"}},
+		{Decibel, unit.Info{Name: "decibel", Description: "logarithmic power ratio"}},
+		{Bel, unit.Info{Name: "bel", Description: "logarithmic power ratio"}},
+		{Neper, unit.Info{Name: "neper", Description: "logarithmic field ratio"}},
+	} {
+		d.info.System = "SI"
+		Registry.Describe(d.m, d.info)
+	}
+}
+
+// inv returns the exact reciprocal of the decimal number s.
+func inv(s string) *big.Rat {
+	r, _ := new(big.Rat).SetString(s)
+	return r.Inv(r)
+}
+
 // FromDuration converts a time.Duration to a unit.Value with unit Second.
 func FromDuration(d time.Duration) unit.Value {
 	return Second(d.Seconds())
@@ -117,22 +242,23 @@ func ToDuration(v unit.Value) (d time.Duration, ok bool) {
 
 // FromCelsius converts a DegCelsius value on the Celsius scale to its
 // corresponding value on the Kelvin scale.  Returns false if v cannot
-// be converted to DegCelsius.
+// be converted to DegCelsius.  This is equivalent to converting v to
+// Kelvin.
 func FromCelsius(v unit.Value) (unit.Value, bool) {
-	c, remain := v.Convert(DegCelsius)
+	k, remain := v.Convert(Kelvin)
 	if !remain.Empty() {
 		return unit.Value{}, false
 	}
-	return Kelvin(c.S + 273.15), true
+	return k, true
 }
 
 // ToCelsius converts a Kelvin value on the Kelvin scale to its corresponding
 // DegCelsius value on the Celsius scale.  Returns false if v cannot be
-// converted to Kelvin.
+// converted to Kelvin.  This is equivalent to converting v to DegCelsius.
 func ToCelsius(v unit.Value) (unit.Value, bool) {
-	k, remain := v.Convert(Kelvin)
+	c, remain := v.Convert(DegCelsius)
 	if !remain.Empty() {
 		return unit.Value{}, false
 	}
-	return DegCelsius(k.S - 273.15), true
+	return c, true
 }
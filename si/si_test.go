@@ -1,6 +1,7 @@
 package si_test
 
 import (
+	"fmt"
 	"math"
 	"testing"
 
@@ -72,3 +73,282 @@ func TestPrefixes(t *testing.T) {
 		t.Errorf("expected %q, got %q", e, r)
 	}
 }
+
+func TestLogarithmic(t *testing.T) {
+	for _, c := range []struct {
+		a        unit.Value
+		to       unit.Maker
+		expected unit.Value
+	}{
+		{si.DBm(30), si.Watt, si.Watt(1)},
+		{si.Watt(0.001), si.DBm, si.DBm(0)},
+		{si.Milli(si.Watt)(100), si.DBm, si.DBm(20)},
+		{si.DBm(30), si.DBW, si.DBW(0)},
+		{si.DBV(20), si.Volt, si.Volt(10)},
+		{si.Volt(0.1), si.DBV, si.DBV(-20)},
+		{si.DBSPL(94), si.Pascal, si.Pascal(1.0024)},
+		{si.PH(7), si.Mole.Div(si.Liter), si.Mole.Div(si.Liter)(1e-7)},
+		{si.Bel(2), si.Decibel, si.Decibel(20)},
+		{si.Neper(1), si.Decibel, si.Decibel(20 / math.Ln10)},
+		{si.Decibel(20), unit.Unity, unit.Scalar(100)(1)},
+	} {
+		r, remain := c.a.Convert(c.to)
+		if !remain.Empty() || !r.Approx(c.expected, 0.0001) || !r.Units().Equal(c.expected.Units()) {
+			t.Errorf("%q.Convert(%v) should give %q, got %q (remain=%q)", c.a, c.to, c.expected, r, remain)
+		}
+	}
+
+	if r, remain := si.DBm(0).Convert(si.Volt); remain.Empty() {
+		t.Errorf("dBm should not convert to V, got %q", r)
+	}
+	if !si.DBm(30).Approx(si.Watt(1), 0.000001) {
+		t.Errorf("30 dBm should equal 1 W")
+	}
+	if v, err := unit.Parse("7 pH", si.Chem, true); err != nil || !v.Equal(si.PH(7)) {
+		t.Errorf("7 pH should parse as acidity in Chem, got %v (err=%v)", v, err)
+	}
+	if v, err := unit.Parse("7 pH", &si.Registry, true); err != nil || !v.Equal(si.Pico(si.Henry)(7)) {
+		t.Errorf("7 pH should parse as picohenries in Registry, got %v (err=%v)", v, err)
+	}
+}
+
+func TestLogarithmicArithmetic(t *testing.T) {
+	for _, c := range []struct {
+		desc     string
+		a, b     unit.Value
+		add      bool
+		expected unit.Value
+		ok       bool
+	}{
+		{"power sum", si.DBm(10), si.DBm(10), true, si.DBm(10 + 10*math.Log10(2)), true},
+		{"power sum mixed", si.DBm(30), si.DBW(0), true, si.DBm(30 + 10*math.Log10(2)), true},
+		{"power sum linear", si.DBm(0), si.Milli(si.Watt)(1), true, si.DBm(10 * math.Log10(2)), true},
+		{"power difference", si.DBm(10 + 10*math.Log10(2)), si.DBm(10), false, si.DBm(10), true},
+		{"gain", si.DBm(10), si.Decibel(3), true, si.DBm(13), true},
+		{"gain on field", si.DBV(10), si.Decibel(6), true, si.DBV(16), true},
+		{"gain first", si.Decibel(3), si.DBm(10), true, si.DBm(13), true},
+		{"loss", si.DBm(10), si.Decibel(3), false, si.DBm(7), true},
+		{"gains", si.Decibel(3), si.Bel(1), true, si.Decibel(13), true},
+		{"gain minus level", si.Decibel(3), si.DBm(10), false, unit.Value{}, false},
+		{"mismatch", si.DBm(10), si.DBV(10), true, unit.Value{}, false},
+		{"equal levels", si.DBm(10), si.DBm(10), false, unit.Value{}, false},
+		{"larger level", si.DBm(10), si.DBW(0), false, unit.Value{}, false},
+	} {
+		t.Run(c.desc, func(t *testing.T) {
+			var r unit.Value
+			var ok bool
+			if c.add {
+				r, ok = c.a.Add(c.b)
+			} else {
+				r, ok = c.a.Sub(c.b)
+			}
+			if ok != c.ok || (ok && (!r.Approx(c.expected, 0.000001) || !r.Units().Equal(c.expected.Units()))) {
+				t.Errorf("expected %q (ok=%v), got %q (ok=%v)", c.expected, c.ok, r, ok)
+			}
+		})
+	}
+}
+
+func TestComplexImpedance(t *testing.T) {
+	z := si.Kilo(si.Ohm).Complex(1 + 2i)
+	r, remain := z.Convert(si.Ohm)
+	if !remain.Empty() || r.C != 1000+2000i || !r.U.Equal(si.Ohm.Units()) {
+		t.Errorf("%v should convert to 1000+j2000 Ω, got %v (remain=%q)", z, r, remain)
+	}
+
+	c, err := unit.ParseComplex("50+j30 kΩ", &si.Registry, true)
+	if err != nil || !c.Abs().Approx(si.Ohm(1000*math.Hypot(50, 30)), 1e-6) {
+		t.Errorf("ParseComplex should give a 58.3 kΩ impedance, got %v (err=%v)", c, err)
+	}
+}
+
+func TestQuantity(t *testing.T) {
+	d, ok := si.From[si.Length](si.Kilo(si.Metre)(1.5))
+	if !ok || d.SI() != 1500 {
+		t.Errorf("1.5 km should be 1500 m, got %v (ok=%v)", d, ok)
+	}
+	if _, ok := si.From[si.Length](si.Second(1)); ok {
+		t.Errorf("1 s should not convert to a Length")
+	}
+
+	// si.Of[si.Length](1).Add(si.Of[si.Time](1)) does not compile.
+	tm := si.Of[si.Time](60).Add(si.Of[si.Time](15))
+	v := si.VelocityTime.Div(d, tm)
+	if e := si.Metre.Div(si.Second)(20); !v.Value().Equal(e) {
+		t.Errorf("%v / %v should be %v, got %v", d, tm, e, v)
+	}
+	if r := si.VelocityTime.DivA(d, v); r != tm {
+		t.Errorf("%v / %v should be %v, got %v", d, v, tm, r)
+	}
+	if r := si.VelocityTime.Mul(v, tm.DivN(3)); r.SI() != 500 {
+		t.Errorf("%v × 25 s should be 500 m, got %v", v, r)
+	}
+
+	f := si.MassAcceleration.Mul(si.Of[si.Mass](2), si.Of[si.Acceleration](9.81))
+	if e := si.Newton(19.62); !f.Value().Approx(e, 1e-9) || !f.Value().Units().Equal(si.Newton.Units()) {
+		t.Errorf("2 kg × 9.81 m/s^2 should be %v, got %v", e, f)
+	}
+	if r, ok := si.ForceLength.Mul(f, si.Of[si.Length](2)).In(si.Kilo(si.Joule)); !ok || !r.Approx(si.Kilo(si.Joule)(0.03924), 1e-9) {
+		t.Errorf("%v × 2 m should be 0.03924 kJ, got %v (ok=%v)", f, r, ok)
+	}
+	if _, ok := f.In(si.Joule); ok {
+		t.Errorf("a Force should not convert to J")
+	}
+	if !si.Of[si.Length](1).Less(d) {
+		t.Errorf("1 m should be less than %v", d)
+	}
+}
+
+func TestNewRelation(t *testing.T) {
+	if r := si.NewRelation[si.Area, si.Length, si.Volume](); r.Mul(si.Of[si.Area](2), si.Of[si.Length](3)).SI() != 6 {
+		t.Errorf("2 m^2 × 3 m should be 6 m^3")
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("a relation of m × s = m^2 should panic")
+		} else if got, want := fmt.Sprint(r), "si.Relation: m × s is not m^2"; got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	}()
+	si.NewRelation[si.Length, si.Time, si.Area]()
+}
+
+func TestKinds(t *testing.T) {
+	if _, remain := si.Sievert(5).Convert(si.Gray); remain.Empty() {
+		t.Errorf("Sv should not convert to Gy")
+	}
+	if _, remain := si.Hertz(5).Convert(si.Becquerel); remain.Empty() {
+		t.Errorf("Hz should not convert to Bq")
+	}
+	if r, remain := si.Kilo(si.Becquerel)(2).Convert(si.Becquerel); !remain.Empty() || !r.Equal(si.Becquerel(2000)) {
+		t.Errorf("2 kBq should convert to 2000 Bq, got %v (remain %q)", r, remain)
+	}
+
+	dose, ok := si.Sievert(5).As(si.KindAbsorbedDose)
+	if !ok {
+		t.Fatalf("Sv should be markable as absorbed dose")
+	}
+	if r, remain := dose.Convert(si.Gray); !remain.Empty() || !r.Equal(si.Gray(5)) {
+		t.Errorf("%v should convert to 5 Gy, got %v (remain %q)", dose, r, remain)
+	}
+
+	// Torque and energy share units, and N m converts freely to J, but
+	// not once it is a torque, in NewtonMetre or marked as one.
+	nm := si.Newton.Mul(si.Metre)(5)
+	if r, remain := nm.Convert(si.Joule); !remain.Empty() || !r.Equal(si.Joule(5)) {
+		t.Errorf("%v should convert to 5 J, got %v (remain %q)", nm, r, remain)
+	}
+	if r, remain := si.Joule(5).Convert(si.NewtonMetre); remain.Empty() {
+		t.Errorf("J should not convert to torque, got %v", r)
+	}
+	if _, err := si.NewtonMetre(5).ConvertErr(si.Joule); err == nil {
+		t.Errorf("torque should not convert to J")
+	}
+	if r, remain := nm.Convert(si.NewtonMetre); !remain.Empty() || r.Kind() != si.KindTorque {
+		t.Errorf("%v should convert to a torque, got %v of kind %v (remain %q)", nm, r, r.Kind(), remain)
+	}
+	torque, ok := si.Joule(5).As(si.KindTorque)
+	if !ok {
+		t.Fatalf("J should be markable as torque")
+	}
+	if !torque.Equal(si.NewtonMetre(5)) {
+		t.Errorf("%v should equal %v", torque, si.NewtonMetre(5))
+	}
+	if got, want := torque.String(), "5 N·m"; got != want {
+		t.Errorf("torque should be formatted as %q, got %q", want, got)
+	}
+	if _, remain := torque.Convert(si.Joule); remain.Empty() {
+		t.Errorf("torque should not convert to J")
+	}
+	if r, remain := torque.Convert(si.Kilo(si.Newton).Mul(si.Metre)); !remain.Empty() || r.Kind() != si.KindTorque {
+		t.Errorf("torque converted to kN m should remain a torque, got %v of kind %v", r, r.Kind())
+	}
+}
+
+func TestAngles(t *testing.T) {
+	if _, remain := si.Radian.Div(si.Second)(1).Convert(si.Hertz); remain.Empty() {
+		t.Errorf("rad/s should not convert to Hz")
+	}
+	if si.Radian(2 * math.Pi).Equal(unit.Value{S: 2 * math.Pi}) {
+		t.Errorf("2π rad should not equal 2π")
+	}
+	// lm = cd sr, and sr does not cancel.
+	if lm := si.Lumen(1).Reduce(); !lm.U.Equiv(si.Candela.Mul(si.Steradian).Units()) {
+		t.Errorf("lm should reduce to cd sr, got %v", lm)
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	// J is of kind energy, so it's used for values of that kind or of
+	// none, but not for torque.
+	energy, _ := si.Kilogram.Mul(si.Metre.Pow(2)).Div(si.Second.Pow(2))(1).As(si.KindEnergy)
+	for _, c := range []struct {
+		v        unit.Value
+		expected string
+	}{
+		{energy, "1 J"},
+		{si.Kilogram.Mul(si.Metre.Pow(2)).Div(si.Second.Pow(2))(1), "1 J"},
+		{si.Newton.Mul(si.Metre)(3), "3 J"},
+		{si.Kilogram.Mul(si.Metre.Pow(2)).Div(si.Second.Pow(3)).Div(si.Ampere)(1), "1 V"},
+		{si.Volt.Div(si.Ampere)(3), "3 Ω"},
+		{si.Candela.Mul(si.Steradian).Div(si.Metre.Pow(2))(2), "2 lx"},
+		{si.Metre.Div(si.Second)(1), "1 m/s"},
+		{si.Sievert(1), "1 Sv"},
+	} {
+		if got := c.v.Simplify(si.Preferred...).String(); got != c.expected {
+			t.Errorf("%v should simplify to %q, got %q", c.v, c.expected, got)
+		}
+	}
+	torque, _ := si.Newton.Mul(si.Metre)(5).As(si.KindTorque)
+	if r := torque.Simplify(si.Preferred...); !r.U.Equal(torque.U) {
+		t.Errorf("torque should not simplify to J, got %v", r)
+	}
+	f := unit.NewFormatter(unit.WithSimplify(si.Preferred...))
+	if got, want := f.Format(torque), "5 N·m"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestLongNames(t *testing.T) {
+	for _, x := range []struct {
+		expr     string
+		expected unit.Value
+	}{
+		{"5 metres", si.Metre(5)},
+		{"2 siemens", si.Siemens(2)},
+		{"60 hertz", si.Hertz(60)},
+		{"3 kilograms", si.Kilogram(3)},
+		{"4 millivolts", si.Milli(si.Volt)(4)},
+		{"3 kgrams", unit.Value{}}, // symbols take only symbol prefixes
+		{"20 degrees Celsius", unit.Value{}},
+	} {
+		v, err := unit.Parse(x.expr, &si.Registry, true)
+		if x.expected.U.Empty() {
+			if err == nil {
+				t.Errorf("%q should fail, got %v", x.expr, v)
+			}
+			continue
+		}
+		if err != nil || !v.Equal(x.expected) {
+			t.Errorf("%q should give %v, got %v (err=%v)", x.expr, x.expected, v, err)
+		}
+	}
+	if i, ok := si.Registry.UnitInfo(si.Henry.Unit()); !ok || i.Plural != "henries" || i.System != "SI" {
+		t.Errorf("H should be described as henries, got %+v", i)
+	}
+	f := unit.NewFormatter(unit.WithLongNames(&si.Registry))
+	for _, x := range []struct {
+		v        unit.Value
+		expected string
+	}{
+		{si.Metre.Div(si.Second.Pow(2))(9.8), "9.8 metres per second^2"},
+		{si.Kilogram.Mul(si.Metre)(2), "2 kilogram metres"},
+		{si.Kilo(si.Metre)(3), "3 kilometres"},
+		{si.Micro(si.Second)(1), "1 microsecond"},
+		{si.DegCelsius(20), "20 degrees Celsius"},
+	} {
+		if got := f.Format(x.v); got != x.expected {
+			t.Errorf("expected %q, got %q", x.expected, got)
+		}
+	}
+}
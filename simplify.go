@@ -0,0 +1,364 @@
+package unit
+
+import "fmt"
+
+// maxSimplifyPower is the largest power of a single unit that Simplify
+// will try in one step.
+const maxSimplifyPower = 3
+
+// simplifier rewrites units as products of powers of a preferred set of
+// units.  Each unit in the set is described by its coordinates in a basis
+// chosen from the set itself, in order, so that a set listing the base
+// units of a system first will measure other units in terms of them.
+type simplifier struct {
+	units  []Unit
+	kinds  []*Kind        // kind of each unit, if any
+	coords [][]rat        // coordinates of each unit in the basis
+	rows   []basisRow     // echelon form of the basis, for solving
+	prims  map[string]int // column of each primitive symbol spanned
+}
+
+// basisRow is a row of the basis in echelon form: v, in primitive
+// dimensions, is the combination e of basis units, and v is zero in the
+// pivot columns of the rows before it.
+type basisRow struct {
+	pivot int
+	v, e  []rat
+}
+
+func newSimplifier(set []Maker) *simplifier {
+	s := &simplifier{prims: make(map[string]int)}
+	for _, m := range set {
+		u := m.Unit()
+		if u == nil {
+			panic(fmt.Sprintf("Simplify: %q is not a singular unit", m.Units()))
+		}
+		s.units = append(s.units, u)
+		s.kinds = append(s.kinds, u.Units().Kind())
+		for _, t := range unitDims(u).t {
+			if _, ok := s.prims[t.u.Symbol()]; !ok {
+				s.prims[t.u.Symbol()] = len(s.prims)
+			}
+		}
+	}
+	for _, u := range s.units {
+		v := s.vector(unitDims(u).t)
+		c, ok := s.solve(v)
+		if !ok {
+			s.extend(v)
+			c, _ = s.solve(v)
+		}
+		s.coords = append(s.coords, c)
+	}
+	// Extending the basis lengthens the coordinates of later units.
+	for i, c := range s.coords {
+		s.coords[i] = s.pad(c)
+	}
+	return s
+}
+
+// vector returns the terms ts as a vector in primitive dimensions.  Terms
+// for primitives not spanned by the set are ignored.
+func (s *simplifier) vector(ts []term) []rat {
+	v := make([]rat, len(s.prims))
+	for i := range v {
+		v[i] = rat{0, 1}
+	}
+	for _, t := range ts {
+		if i, ok := s.prims[t.u.Symbol()]; ok {
+			v[i] = t.e
+		}
+	}
+	return v
+}
+
+func (s *simplifier) pad(c []rat) []rat {
+	for len(c) < len(s.rows) {
+		c = append(c, rat{0, 1})
+	}
+	return c
+}
+
+// reduce eliminates the pivot columns of the basis from v, returning the
+// remainder and the combination of basis units eliminated.
+func (s *simplifier) reduce(v []rat) (rem, e []rat) {
+	rem = append([]rat(nil), v...)
+	e = s.pad(nil)
+	for _, r := range s.rows {
+		f := rem[r.pivot]
+		if f.n == 0 {
+			continue
+		}
+		for i := range rem {
+			rem[i] = rem[i].sub(f.mul(r.v[i]))
+		}
+		for i := range r.e {
+			e[i] = e[i].add(f.mul(r.e[i]))
+		}
+	}
+	return rem, e
+}
+
+// solve returns the coordinates of v in the basis, or false if v is not
+// spanned by it.
+func (s *simplifier) solve(v []rat) ([]rat, bool) {
+	rem, e := s.reduce(v)
+	for _, x := range rem {
+		if x.n != 0 {
+			return nil, false
+		}
+	}
+	return e, true
+}
+
+// extend adds v, which must not be spanned by the basis, to it.
+func (s *simplifier) extend(v []rat) {
+	rem, e := s.reduce(v)
+	for i := range s.rows {
+		s.rows[i].e = append(s.rows[i].e, rat{0, 1})
+	}
+	for i := range e {
+		e[i] = e[i].neg()
+	}
+	e = append(e, rat{1, 1})
+	pivot := 0
+	for rem[pivot].n == 0 {
+		pivot++
+	}
+	f := rem[pivot]
+	for i := range rem {
+		rem[i] = rem[i].quo(f)
+	}
+	for i := range e {
+		e[i] = e[i].quo(f)
+	}
+	s.rows = append(s.rows, basisRow{pivot, rem, e})
+}
+
+// step is a unit from the set raised to a power.
+type step struct {
+	i int // index into the set
+	p int
+}
+
+// expr is a product of steps, scored by its total power, then by the
+// number of distinct units it uses, then by the positions of those units
+// in the set.
+type expr []step
+
+func (x expr) power() (n int) {
+	for _, st := range x {
+		if st.p < 0 {
+			n -= st.p
+		} else {
+			n += st.p
+		}
+	}
+	return
+}
+
+func (x expr) distinct() int {
+	seen := map[int]bool{}
+	for _, st := range x {
+		seen[st.i] = true
+	}
+	return len(seen)
+}
+
+func (x expr) less(y expr) bool {
+	if px, py := x.power(), y.power(); px != py {
+		return px < py
+	}
+	if dx, dy := x.distinct(), y.distinct(); dx != dy {
+		return dx < dy
+	}
+	for k := 0; k < len(x) && k < len(y); k++ {
+		if x[k].i != y[k].i {
+			return x[k].i < y[k].i
+		}
+		if x[k].p != y[k].p {
+			return x[k].p > y[k].p
+		}
+	}
+	return len(x) < len(y)
+}
+
+// size returns the sum of the magnitudes of the coordinates c.
+func size(c []rat) rat {
+	r := rat{0, 1}
+	for _, x := range c {
+		r = r.add(x.abs())
+	}
+	return r
+}
+
+// sizeAfter returns the size of c less the coordinates of st, without
+// computing them.
+func (s *simplifier) sizeAfter(c []rat, st step) rat {
+	r := rat{0, 1}
+	p := rat{st.p, 1}
+	for j := range c {
+		r = r.add(c[j].sub(p.mul(s.coords[st.i][j])).abs())
+	}
+	return r
+}
+
+// apply returns c less the coordinates of st.
+func (s *simplifier) apply(c []rat, st step) []rat {
+	r := make([]rat, len(c))
+	p := rat{st.p, 1}
+	for j := range c {
+		r[j] = c[j].sub(p.mul(s.coords[st.i][j]))
+	}
+	return r
+}
+
+// candidates returns the steps that most reduce the size of c, in order
+// of preference, using only units of no kind or of kind k, or any unit if
+// k is nil.  If first is
+// true, every step that reduces it at all is returned.
+func (s *simplifier) candidates(c []rat, k *Kind, first bool) (r []step) {
+	cur := size(c)
+	var best rat
+	for i := range s.units {
+		if k != nil && s.kinds[i] != nil && s.kinds[i] != k {
+			continue
+		}
+		for q := 1; q <= maxSimplifyPower; q++ {
+			for _, p := range [2]int{q, -q} {
+				sz := s.sizeAfter(c, step{i, p})
+				if !sz.less(cur) {
+					continue
+				}
+				switch {
+				case first:
+					r = append(r, step{i, p})
+				case r == nil || sz.less(best):
+					r, best = []step{{i, p}}, sz
+				}
+			}
+		}
+	}
+	return
+}
+
+// greedy completes x, which leaves the coordinates c unaccounted for, by
+// repeatedly taking the step that most reduces them.  Returns false if c
+// cannot be reduced to zero this way.
+func (s *simplifier) greedy(x expr, c []rat, k *Kind) (expr, bool) {
+	for size(c).n != 0 {
+		next := s.candidates(c, k, false)
+		if next == nil {
+			return nil, false
+		}
+		x = append(x, next[0])
+		c = s.apply(c, next[0])
+	}
+	return x, true
+}
+
+// simplify returns the best expression with coordinates c found by
+// greedily completing each possible first step, using only units
+// compatible with kind k (see candidates).
+func (s *simplifier) simplify(c []rat, k *Kind) (best expr, ok bool) {
+	for _, st := range s.candidates(c, k, true) {
+		x, found := s.greedy(expr{st}, s.apply(c, st), k)
+		if found && (best == nil || x.less(best)) {
+			best, ok = x, true
+		}
+	}
+	return
+}
+
+// unitsOf returns x as Units.
+func (s *simplifier) unitsOf(x expr) Units {
+	var ts []term
+outer:
+	for _, st := range x {
+		for k := range ts {
+			if ts[k].u.Equal(s.units[st.i]) {
+				ts[k].e = ts[k].e.add(rat{st.p, 1})
+				continue outer
+			}
+		}
+		ts = append(ts, term{s.units[st.i], rat{st.p, 1}})
+	}
+	return fromTerms(ts)
+}
+
+// rewrite returns a expressed in the most compact product of powers of the
+// simplifier's units, or a unchanged if no such product is more compact
+// than a's own units.
+func (s *simplifier) rewrite(a Value) Value {
+	if a.U.Empty() || a.U.absolute() != nil || a.U.logarithmic() != nil {
+		return a
+	}
+	// Primitives the set doesn't span are kept as they are.
+	var spanned, rest []term
+	for _, t := range a.U.dims().t {
+		if _, ok := s.prims[t.u.Symbol()]; ok {
+			spanned = append(spanned, t)
+		} else {
+			rest = append(rest, t)
+		}
+	}
+	c, ok := s.solve(s.vector(spanned))
+	if !ok {
+		return a
+	}
+	for _, x := range c {
+		if !x.isInt() {
+			return a
+		}
+	}
+	x, ok := s.simplify(c, a.Kind())
+	if !ok {
+		return a
+	}
+	var orig, extra int
+	for _, t := range a.U.terms() {
+		orig += t.e.abs().n
+	}
+	for _, t := range rest {
+		extra += t.e.abs().n
+	}
+	if x.power()+extra >= orig {
+		return a
+	}
+	u := s.unitsOf(x).Mul(fromTerms(rest))
+	r, remain := a.Convert(u.Make)
+	if !remain.Empty() {
+		return a
+	}
+	if a.R == nil {
+		// Avoid rounding error in the factor, if it's known exactly.
+		if f := quoRat(a.U.exactFactor(), u.exactFactor()); f != nil {
+			ff, _ := f.Float64()
+			r.S = a.S * ff
+		}
+	}
+	return r
+}
+
+// Simplify returns a with its units rewritten as the most compact product
+// of powers of the units in set, such as "1 J" for "1 kg m^2/s^2" and
+// "1 V" for "1 kg m^2/s^3/A".  The set should list the base units of a
+// system first, followed by the derived units to prefer, since units are
+// measured in terms of those listed before them.
+//
+// Expressions are compared by the sum of the magnitudes of their
+// exponents, then by the number of distinct units they use, and then by
+// the order in which those units appear in set, and the search for them is
+// greedy, so that the result is deterministic.  If no expression is more
+// compact than a's own units, or a is affine or logarithmic, a is returned
+// unchanged.  Primitive units that set does not account for are kept.
+// Units in set that are of a kind (see Kind) are not used for values of a
+// different kind, so that "1 N m" marked as a torque is not rewritten as
+// joules.
+// Panics if set contains a Maker that is not a singular unit.
+//
+// Preparing set for the search takes some time, so to simplify many values
+// using the same set, use a Formatter configured WithSimplify.
+func (a Value) Simplify(set ...Maker) Value {
+	return newSimplifier(set).rewrite(a)
+}
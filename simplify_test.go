@@ -0,0 +1,87 @@
+package unit_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dnesting/unit"
+)
+
+func TestSimplify(t *testing.T) {
+	m := unit.Primitive("m")
+	kg := unit.Primitive("kg")
+	s := unit.Primitive("s")
+	a := unit.Primitive("A")
+	n := unit.Derive("N", kg.Mul(m).Div(s.Pow(2)))
+	j := unit.Derive("J", n.Mul(m))
+	w := unit.Derive("W", j.Div(s))
+	v := unit.Derive("V", w.Div(a))
+	km := unit.Derive("km", m(1000))
+	set := []unit.Maker{m, kg, s, a, n, j, w, v}
+	x := unit.Primitive("x") // not spanned by set
+
+	for _, c := range []struct {
+		v        unit.Value
+		expected unit.Value
+	}{
+		{kg.Mul(m.Pow(2)).Div(s.Pow(2))(2), j(2)},
+		{kg.Mul(m.Pow(2)).Div(s.Pow(3)).Div(a)(2), v(2)},
+		{v.Mul(a)(3), w(3)},
+		{n.Mul(m).Div(s)(3), w(3)},
+		{kg.Mul(km).Div(s.Pow(2))(1), n(1000)},
+		{kg.Mul(m.Pow(2)).Div(s.Pow(2)).Mul(x)(2), j.Mul(x)(2)},
+		{kg.Div(s.Pow(3))(1), w.Div(m.Pow(2))(1)},
+
+		// Already as compact as possible.
+		{m.Div(s)(4), m.Div(s)(4)},
+		{km.Div(s)(4), km.Div(s)(4)},
+		{j(5), j(5)},
+		{unit.Value{S: 5}, unit.Value{S: 5}},
+	} {
+		r := c.v.Simplify(set...)
+		if !r.U.Equal(c.expected.U) || !r.Approx(c.expected, 1e-9) {
+			t.Errorf("%v should simplify to %v, got %v", c.v, c.expected, r)
+		}
+	}
+
+	// The result doesn't depend on the order units were multiplied in.
+	p := kg.Mul(m.Pow(2)).Div(s.Pow(3))(1).Simplify(set...)
+	q := unit.Scalar(1).Div(s.Pow(3)).Mul(m.Pow(2)).Mul(kg)(1).Simplify(set...)
+	if !p.U.Equal(q.U) {
+		t.Errorf("simplifying should be deterministic, got %v and %v", p, q)
+	}
+}
+
+func TestFormatterSimplify(t *testing.T) {
+	m := unit.Primitive("m")
+	kg := unit.Primitive("kg")
+	s := unit.Primitive("s")
+	a := unit.Primitive("A")
+	n := unit.Derive("N", kg.Mul(m).Div(s.Pow(2)))
+	j := unit.Derive("J", n.Mul(m))
+	w := unit.Derive("W", j.Div(s))
+	v := unit.Derive("V", w.Div(a))
+	f := unit.NewFormatter(unit.WithSimplify(m, kg, s, a, n, j, w, v))
+	if got, want := f.Format(kg.Mul(m.Pow(2)).Div(s.Pow(2))(1.5)), "1.5 J"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got, want := f.Format(m.Div(s)(2)), "2 m/s"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func ExampleValue_Simplify() {
+	m := unit.Primitive("m")
+	kg := unit.Primitive("kg")
+	s := unit.Primitive("s")
+	n := unit.Derive("N", kg.Mul(m).Div(s.Pow(2)))
+	j := unit.Derive("J", n.Mul(m))
+	w := unit.Derive("W", j.Div(s))
+
+	p := kg.Mul(m.Pow(2)).Div(s.Pow(3))(60)
+	fmt.Println(p)
+	fmt.Println(p.Simplify(m, kg, s, n, j, w))
+	// Output:
+	// 60 kg m^2/s^3
+	// 60 W
+}
@@ -0,0 +1,82 @@
+package unit
+
+import "unicode/utf8"
+
+// prefixTrie maps prefix symbols to prefix functions, so that every prefix
+// that begins a name can be found in a single pass over the name.
+type prefixTrie struct {
+	next  map[rune]*prefixTrie
+	m     func(Maker) Maker // the prefix ending here, if any
+	mult  float64           // its multiplier
+	alias bool              // whether this is an alias for its symbol
+	long  bool              // whether this is its long name, as in "kilo"
+	name  string            // its long name, if this is its symbol
+}
+
+// get returns the entry registered as symbol, or nil.
+func (t *prefixTrie) get(symbol string) *prefixTrie {
+	for _, c := range symbol {
+		if t == nil {
+			return nil
+		}
+		t = t.next[c]
+	}
+	if t == nil || t.m == nil {
+		return nil
+	}
+	return t
+}
+
+// put registers m, which multiplies by mult, as symbol, which is an alias
+// if alias is true, and returns its entry.
+func (t *prefixTrie) put(symbol string, m func(Maker) Maker, mult float64, alias bool) *prefixTrie {
+	for _, c := range symbol {
+		if t.next == nil {
+			t.next = make(map[rune]*prefixTrie)
+		}
+		n := t.next[c]
+		if n == nil {
+			n = &prefixTrie{}
+			t.next[c] = n
+		}
+		t = n
+	}
+	t.m, t.mult, t.alias = m, mult, alias
+	return t
+}
+
+// match calls fn for the entry of each prefix that begins name but is
+// shorter than it, longest first, with the length of the prefix in bytes.
+func (t *prefixTrie) match(name string, fn func(n int, p *prefixTrie)) {
+	t.matchFrom(name, 0, fn)
+}
+
+func (t *prefixTrie) matchFrom(name string, i int, fn func(n int, p *prefixTrie)) {
+	if i >= len(name) || t == nil {
+		return
+	}
+	c, size := utf8.DecodeRuneInString(name[i:])
+	if n := t.next[c]; n != nil {
+		n.matchFrom(name, i+size, fn)
+		if n.m != nil && i+size < len(name) {
+			fn(i+size, n)
+		}
+	}
+}
+
+// walk calls fn for each prefix in t, in no particular order.
+func (t *prefixTrie) walk(fn func(symbol string, p *prefixTrie)) {
+	t.walkFrom(nil, fn)
+}
+
+func (t *prefixTrie) walkFrom(buf []byte, fn func(symbol string, p *prefixTrie)) {
+	if t == nil {
+		return
+	}
+	if t.m != nil {
+		fn(string(buf), t)
+	}
+	for c, n := range t.next {
+		n.walkFrom(utf8.AppendRune(buf, c), fn)
+	}
+}
@@ -0,0 +1,147 @@
+package unit
+
+import "math"
+
+// Uncertain is a qualified value with a standard uncertainty.  Sigma is
+// expressed in the same units as V.  Arithmetic on Uncertain values
+// propagates the uncertainty using first-order (GUM) rules.  Uncertain
+// implements Qualified, representing the value V.
+type Uncertain struct {
+	V     Value
+	Sigma float64
+}
+
+// PlusMinus returns v with the standard uncertainty sigma, expressed in
+// v's units.
+func (v Value) PlusMinus(sigma float64) Uncertain {
+	return Uncertain{V: v, Sigma: math.Abs(sigma)}
+}
+
+// Value returns the scalar (float64) component of u's value.
+func (u Uncertain) Value() float64 { return u.V.S }
+
+// Units returns the Units for u.
+func (u Uncertain) Units() Units { return u.V.U }
+
+// Relative returns the relative standard uncertainty of u, σ/|v|.
+func (u Uncertain) Relative() float64 { return u.Sigma / math.Abs(u.V.S) }
+
+func (u Uncertain) String() string {
+	return DefaultFormatter.FormatUncertain(u)
+}
+
+// withS returns v with its scalar replaced by s.
+func (v Value) withS(s float64) Value {
+	v.S = s
+	v.R = nil
+	return v
+}
+
+// sensitivity estimates the derivative of f at x with a central
+// difference of step h, where h is the uncertainty in x.  For the linear
+// (or affine) functions used in conversions and sums this is exact, and
+// for logarithmic units it is a first-order approximation.
+func sensitivity(f func(x float64) float64, x, h float64) float64 {
+	if h == 0 {
+		return 0
+	}
+	return (f(x+h) - f(x-h)) / (2 * h)
+}
+
+// combine returns the uncertainty of a result whose sensitivities to a
+// and b are ka and kb, given the correlation coefficient rho between them.
+func combine(ka, sa, kb, sb, rho float64) float64 {
+	ua, ub := ka*sa, kb*sb
+	return math.Sqrt(math.Max(0, ua*ua+ub*ub+2*rho*ua*ub))
+}
+
+// Convert converts u into the units of wanted, as described in
+// Value.Convert, scaling the uncertainty accordingly.  Offsets, such as
+// those of affine units, do not affect the uncertainty.
+func (u Uncertain) Convert(wanted Maker) (result Uncertain, remain Units) {
+	result.V, remain = u.V.Convert(wanted)
+	if !remain.Empty() {
+		return
+	}
+	f := func(x float64) float64 {
+		r, _ := u.V.withS(x).Convert(wanted)
+		return r.S
+	}
+	result.Sigma = math.Abs(sensitivity(f, u.V.S, u.Sigma) * u.Sigma)
+	return
+}
+
+// Add adds a and b as described in Value.Add, assuming a and b are
+// uncorrelated.
+func (a Uncertain) Add(b Uncertain) (Uncertain, bool) {
+	return a.AddCorr(b, 0)
+}
+
+// AddCorr adds a and b as described in Value.Add, where rho is the
+// correlation coefficient between a and b.
+func (a Uncertain) AddCorr(b Uncertain, rho float64) (r Uncertain, ok bool) {
+	return a.sum(b, rho, Value.Add)
+}
+
+// Sub subtracts b from a as described in Value.Sub, assuming a and b are
+// uncorrelated.
+func (a Uncertain) Sub(b Uncertain) (Uncertain, bool) {
+	return a.SubCorr(b, 0)
+}
+
+// SubCorr subtracts b from a as described in Value.Sub, where rho is the
+// correlation coefficient between a and b.
+func (a Uncertain) SubCorr(b Uncertain, rho float64) (r Uncertain, ok bool) {
+	return a.sum(b, rho, Value.Sub)
+}
+
+func (a Uncertain) sum(b Uncertain, rho float64, op func(a, b Value) (Value, bool)) (r Uncertain, ok bool) {
+	if r.V, ok = op(a.V, b.V); !ok {
+		return
+	}
+	fa := func(x float64) float64 {
+		v, _ := op(a.V.withS(x), b.V)
+		return v.S
+	}
+	fb := func(x float64) float64 {
+		v, _ := op(a.V, b.V.withS(x))
+		return v.S
+	}
+	ka := sensitivity(fa, a.V.S, a.Sigma)
+	kb := sensitivity(fb, b.V.S, b.Sigma)
+	r.Sigma = combine(ka, a.Sigma, kb, b.Sigma, rho)
+	return
+}
+
+// Mul multiplies a and b, assuming a and b are uncorrelated.
+func (a Uncertain) Mul(b Uncertain) Uncertain {
+	return a.MulCorr(b, 0)
+}
+
+// MulCorr multiplies a and b, where rho is the correlation coefficient
+// between a and b.
+func (a Uncertain) MulCorr(b Uncertain, rho float64) (r Uncertain) {
+	r.V = a.V.Mul(b.V)
+	r.Sigma = combine(b.V.S, a.Sigma, a.V.S, b.Sigma, rho)
+	return
+}
+
+// Div divides a by b, assuming a and b are uncorrelated.
+func (a Uncertain) Div(b Uncertain) Uncertain {
+	return a.DivCorr(b, 0)
+}
+
+// DivCorr divides a by b, where rho is the correlation coefficient
+// between a and b.
+func (a Uncertain) DivCorr(b Uncertain, rho float64) (r Uncertain) {
+	r.V = a.V.Div(b.V)
+	r.Sigma = combine(1/b.V.S, a.Sigma, -a.V.S/(b.V.S*b.V.S), b.Sigma, rho)
+	return
+}
+
+// Pow raises a to the power of n, which may be negative.
+func (a Uncertain) Pow(n int) (r Uncertain) {
+	r.V = a.V.Pow(n)
+	r.Sigma = math.Abs(float64(n) * math.Pow(a.V.S, float64(n-1)) * a.Sigma)
+	return
+}
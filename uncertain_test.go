@@ -0,0 +1,161 @@
+package unit_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/dnesting/unit"
+)
+
+func approxSigma(u unit.Uncertain, sigma float64) bool {
+	return math.Abs(u.Sigma-sigma) < 1e-9
+}
+
+func TestUncertainArithmetic(t *testing.T) {
+	m := unit.Primitive("m")
+	km := unit.Derive("km", m(1000))
+	s := unit.Primitive("s")
+
+	a := m(3).PlusMinus(0.3)
+	b := m(4).PlusMinus(0.4)
+
+	for _, x := range []struct {
+		desc     string
+		r        unit.Uncertain
+		expected unit.Value
+		sigma    float64
+	}{
+		{"add", must(a.Add(b)), m(7), 0.5},
+		{"sub", must(a.Sub(b)), m(-1), 0.5},
+		{"add converted", must(a.Add(km(0.004).PlusMinus(0.0004))), m(7), 0.5},
+		{"add correlated", must(a.AddCorr(b, 1)), m(7), 0.7},
+		{"sub correlated", must(a.SubCorr(b, 1)), m(-1), 0.1},
+		{"mul", a.Mul(b), m.Pow(2)(12), math.Sqrt(4*0.3*4*0.3 + 3*0.4*3*0.4)},
+		{"div", a.Div(s(2).PlusMinus(0.1)), m.Div(s)(1.5), math.Sqrt(0.15*0.15 + 0.075*0.075)},
+		{"div correlated", a.DivCorr(b, 1), unit.Value{S: 0.75}, 0},
+		{"pow", a.Pow(2), m.Pow(2)(9), 1.8},
+		{"exact", a.Mul(m(2).PlusMinus(0)), m.Pow(2)(6), 0.6},
+	} {
+		if !x.r.V.Approx(x.expected, 1e-9) || !x.r.Units().Equal(x.expected.Units()) || !approxSigma(x.r, x.sigma) {
+			t.Errorf("%s: expected %v ± %g, got %v", x.desc, x.expected, x.sigma, x.r)
+		}
+	}
+
+	if r, ok := a.Add(s(1).PlusMinus(0.1)); ok {
+		t.Errorf("adding m and s should fail, got %v", r)
+	}
+}
+
+func must(u unit.Uncertain, ok bool) unit.Uncertain {
+	if !ok {
+		panic("operation failed")
+	}
+	return u
+}
+
+func TestUncertainConvert(t *testing.T) {
+	m := unit.Primitive("m")
+	cm := unit.Derive("cm", m(0.01))
+	k := unit.Primitive("K")
+	c := unit.Affine("°C", unit.Derive("Δ°C", k), 273.15)
+	f := unit.Affine("°F", unit.Derive("Δ°F", k(5.0/9)), 459.67)
+
+	if r, remain := m(1.5).PlusMinus(0.02).Convert(cm); !remain.Empty() || !r.V.Approx(cm(150), 1e-9) || !approxSigma(r, 2) {
+		t.Errorf("1.5 ± 0.02 m should convert to 150 ± 2 cm, got %v (remain=%q)", r, remain)
+	}
+	if r, remain := c(20).PlusMinus(0.5).Convert(f); !remain.Empty() || !r.V.Approx(f(68), 1e-9) || !approxSigma(r, 0.9) {
+		t.Errorf("20 ± 0.5 °C should convert to 68 ± 0.9 °F, got %v (remain=%q)", r, remain)
+	}
+	if r, remain := m(1).PlusMinus(0.1).Convert(c); remain.Empty() {
+		t.Errorf("m should not convert to °C, got %v", r)
+	}
+
+	w := unit.Primitive("W")
+	dBm := unit.Logarithmic("dBm", w(0.001), 1, false)
+	r, remain := dBm(30).PlusMinus(0.1).Convert(w)
+	if expected := math.Ln10 / 100; !remain.Empty() || !r.V.Approx(w(1), 1e-9) || math.Abs(r.Sigma-expected) > 1e-5 {
+		t.Errorf("30 ± 0.1 dBm should convert to 1 ± %.5f W, got %v (remain=%q)", expected, r, remain)
+	}
+}
+
+func TestUncertainFormat(t *testing.T) {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+	accel := m.Div(s.Pow(2))
+	concise := unit.NewFormatter(unit.WithConciseUncertainty())
+
+	for _, x := range []struct {
+		u       unit.Uncertain
+		plain   string
+		concise string
+	}{
+		{accel(9.81).PlusMinus(0.02), "9.81 ± 0.02 m/s^2", "9.81(2) m/s^2"},
+		{m(9.81).PlusMinus(0.015), "9.81 ± 0.015 m", "9.810(15) m"},
+		{m(1234).PlusMinus(25), "1234 ± 25 m", "1230(30) m"},
+		{m(1.5).PlusMinus(0), "1.5 ± 0 m", "1.5 m"},
+		{m(9.81).PlusMinus(0.0096), "9.81 ± 0.0096 m", "9.810(10) m"},
+		{m(9.81).PlusMinus(0.0196), "9.81 ± 0.0196 m", "9.810(20) m"},
+		{m(5e22).PlusMinus(3e20), "5e+22 ± 3e+20 m", "5e+22 ± 3e+20 m"},
+		{m(1).PlusMinus(2e-18), "1 ± 2e-18 m", "1 ± 2e-18 m"},
+	} {
+		if got := x.u.String(); got != x.plain {
+			t.Errorf("expected %q, got %q", x.plain, got)
+		}
+		if got := concise.FormatUncertain(x.u); got != x.concise {
+			t.Errorf("expected %q, got %q", x.concise, got)
+		}
+	}
+}
+
+func TestParseUncertain(t *testing.T) {
+	var r unit.Registry
+	m := r.Primitive("m")
+	s := r.Primitive("s")
+	accel := m.Div(s.Pow(2))
+
+	for _, x := range []struct {
+		str      string
+		expected unit.Value
+		sigma    float64
+		err      bool
+	}{
+		{"9.81 ± 0.02 m/s^2", accel(9.81), 0.02, false},
+		{"9.81 +/- 0.02 m/s^2", accel(9.81), 0.02, false},
+		{"9.81(2) m/s^2", accel(9.81), 0.02, false},
+		{"9.810(15) m", m(9.81), 0.015, false},
+		{"1.23e3(4) m", m(1230), 40, false},
+		{"12 m", m(12), 0, false},
+		{"9.81() m", unit.Value{}, 0, true},
+		{"9.81 ± m", unit.Value{}, 0, true},
+		{"m ± 2", unit.Value{}, 0, true},
+	} {
+		if v, err := unit.Parse(x.str, &r, true); x.err != (err != nil) || (err == nil && !v.Equal(x.expected)) {
+			t.Errorf("Parse(%q) should give %v (fail=%v), got %v (err=%v)", x.str, x.expected, x.err, v, err)
+		}
+		u, err := unit.ParseUncertain(x.str, &r, true)
+		if x.err {
+			if err == nil {
+				t.Errorf("ParseUncertain(%q) should fail, got %v", x.str, u)
+			}
+			continue
+		}
+		if err != nil || !u.V.Equal(x.expected) || !approxSigma(u, x.sigma) {
+			t.Errorf("ParseUncertain(%q) should give %v ± %g, got %v (err=%v)", x.str, x.expected, x.sigma, u, err)
+		}
+	}
+}
+
+func ExampleUncertain() {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+
+	d := m(100).PlusMinus(0.5)
+	t := s(9.58).PlusMinus(0.01)
+	v := d.Div(t)
+	fmt.Println(unit.NewFormatter(unit.WithConciseUncertainty()).FormatUncertain(v))
+	fmt.Println(unit.NewFormatter(unit.WithFmt("%.3g")).FormatUncertain(v))
+	// Output:
+	// 10.44(5) m/s
+	// 10.4 ± 0.0533 m/s
+}
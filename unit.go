@@ -22,15 +22,21 @@ type unitType struct {
 	symbol string
 	deriv  Value
 	units  Units
+	canon  dims
+	kind   *Kind
+
+	dimensionless bool
 }
 
-func (u unitType) String() string       { return fmt.Sprintf("Unit(%q = %v)", u.Symbol(), u.Deriv()) }
-func (u unitType) Symbol() string       { return u.symbol }
-func (u unitType) Deriv() Value         { return u.deriv }
-func (u unitType) Units() Units         { return u.units }
-func (u unitType) Value() float64       { return 1 }
-func (u unitType) Make(v float64) Value { return u.units.Make(v) }
-func (u unitType) Equal(o Unit) bool    { return unitEqual(u, o) }
+func (u *unitType) String() string       { return fmt.Sprintf("Unit(%q = %v)", u.Symbol(), u.Deriv()) }
+func (u *unitType) Symbol() string       { return u.symbol }
+func (u *unitType) Deriv() Value         { return u.deriv }
+func (u *unitType) Units() Units         { return u.units }
+func (u *unitType) Value() float64       { return 1 }
+func (u *unitType) Make(v float64) Value { return u.units.Make(v) }
+func (u *unitType) Equal(o Unit) bool    { return unitEqual(u, o) }
+func (u *unitType) dims() dims           { return u.canon }
+func (u *unitType) unitKind() *Kind      { return u.kind }
 
 func unitEqual(a, b Unit) bool {
 	if a == nil {
@@ -39,12 +45,29 @@ func unitEqual(a, b Unit) bool {
 	if b == nil {
 		return false
 	}
+	if identical(a, b) {
+		return true
+	}
 	if a.Symbol() != b.Symbol() {
 		return false
 	}
 	return a.Deriv().Equal(b.Deriv())
 }
 
+// identical returns true if a and b are the same instance of one of this
+// package's unit types, which is much cheaper to check than comparing
+// their derivations.
+func identical(a, b Unit) bool {
+	switch a := a.(type) {
+	case *unitType, *rootType, *affineType, *logType:
+		return a == b
+	case prefixType:
+		o, ok := b.(prefixType)
+		return ok && a.prefix == o.prefix && a.mult == o.mult && identical(a.inner, o.inner)
+	}
+	return false
+}
+
 // Scalar returns a Maker that produces unitless values multiplied by v.
 func Scalar(v float64) Maker { return Value{S: v}.MulN }
 
@@ -122,6 +145,7 @@ func newUnit(symbol string, value Qualified) *unitType {
 	// We populate u.units once so we don't have to re-allocate it
 	// for every value derived from the unit.
 	u.units = Units{N: []Unit{u}}
+	u.canon = derivDims(u).memo()
 	return u
 }
 
@@ -161,6 +185,7 @@ type prefixType struct {
 	mult   float64
 	inner  Unit
 	units  Units
+	canon  dims
 }
 
 func (u prefixType) Symbol() string    { return u.prefix + u.inner.Symbol() }
@@ -168,6 +193,8 @@ func (u prefixType) Deriv() Value      { return u.inner.Make(u.mult) }
 func (u prefixType) Equal(o Unit) bool { return unitEqual(u, o) }
 func (u prefixType) Units() Units      { return u.units }
 func (u prefixType) Value() float64    { return 1 }
+func (u prefixType) dims() dims        { return u.canon }
+func (u prefixType) unitKind() *Kind   { return Units{N: []Unit{u.inner}}.Kind() }
 func (u prefixType) String() string {
 	return fmt.Sprintf("Prefix(%q = %g*%v)", u.prefix, u.mult, u.inner)
 }
@@ -190,6 +217,10 @@ func Prefix(symbol string, mult float64) func(inner Maker) Maker {
 			mult:   mult,
 			inner:  iu,
 		}
+		p.canon = unitDims(iu)
+		p.canon.f *= mult
+		p.canon.r = mulRat(p.canon.r, decimal(mult))
+		p.canon = p.canon.memo()
 		p.units = Units{N: []Unit{p}}
 		return p.Make
 	}
@@ -0,0 +1,402 @@
+package unitdef
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dnesting/unit"
+)
+
+// operators lists the characters that may not appear in unit names.
+const operators = "+-*/|^()~;,[] \t"
+
+// maxExpDenom is the largest denominator tried when interpreting a
+// fractional exponent as a rational power.
+const maxExpDenom = 12
+
+// builtins are the functions of unitless numbers GNU Units provides.
+var builtins = map[string]func(float64) float64{
+	"sqrt":     math.Sqrt,
+	"cuberoot": math.Cbrt,
+	"exp":      math.Exp,
+	"ln":       math.Log,
+	"log":      math.Log10,
+	"log2":     math.Log2,
+	"sin":      math.Sin,
+	"cos":      math.Cos,
+	"tan":      math.Tan,
+	"asin":     math.Asin,
+	"acos":     math.Acos,
+	"atan":     math.Atan,
+	"sinh":     math.Sinh,
+	"cosh":     math.Cosh,
+	"tanh":     math.Tanh,
+	"asinh":    math.Asinh,
+	"acosh":    math.Acosh,
+	"atanh":    math.Atanh,
+	"floor":    math.Floor,
+	"ceil":     math.Ceil,
+	"round":    math.Round,
+	"trunc":    math.Trunc,
+}
+
+// evaluator evaluates an expression in the syntax of GNU Units, where
+// juxtaposition multiplies with higher precedence than "*" and "/", "|"
+// divides numbers with the highest precedence, and "^" is right
+// associative.
+type evaluator struct {
+	d    *Defs
+	s    string
+	pos  int
+	vars map[string]unit.Value
+}
+
+// eval evaluates the expression s.  Names in vars are bound to the values
+// given, taking precedence over units of the same name.
+func (d *Defs) eval(s string, vars map[string]unit.Value) (unit.Value, error) {
+	e := &evaluator{d: d, s: s, vars: vars}
+	if strings.TrimSpace(s) == "" {
+		return unit.Value{}, errors.New("empty expression")
+	}
+	v, err := e.sum()
+	if err != nil {
+		return unit.Value{}, err
+	}
+	if e.skip(); e.pos < len(e.s) {
+		return unit.Value{}, fmt.Errorf("unexpected %q in %q", e.s[e.pos:], s)
+	}
+	return v, nil
+}
+
+func (e *evaluator) skip() {
+	for e.pos < len(e.s) {
+		r, n := utf8.DecodeRuneInString(e.s[e.pos:])
+		if !unicode.IsSpace(r) {
+			return
+		}
+		e.pos += n
+	}
+}
+
+// peek returns the next character after any spaces, or 0 at the end.
+func (e *evaluator) peek() byte {
+	e.skip()
+	if e.pos < len(e.s) {
+		return e.s[e.pos]
+	}
+	return 0
+}
+
+// accept consumes op if it is next.
+func (e *evaluator) accept(op string) bool {
+	e.skip()
+	if strings.HasPrefix(e.s[e.pos:], op) {
+		e.pos += len(op)
+		return true
+	}
+	return false
+}
+
+// acceptWord consumes the word w if it is next and not part of a longer
+// name.
+func (e *evaluator) acceptWord(w string) bool {
+	save := e.pos
+	if !e.accept(w) {
+		return false
+	}
+	if e.pos < len(e.s) && !strings.ContainsRune(operators, rune(e.s[e.pos])) {
+		e.pos = save
+		return false
+	}
+	return true
+}
+
+// sum := product { ("+" | "-") product }
+func (e *evaluator) sum() (unit.Value, error) {
+	a, err := e.product()
+	if err != nil {
+		return a, err
+	}
+	for {
+		var op func(unit.Value, unit.Value) (unit.Value, error)
+		switch {
+		case e.accept("+"):
+			op = unit.Value.AddErr
+		case e.accept("-"):
+			op = unit.Value.SubErr
+		default:
+			return a, nil
+		}
+		b, err := e.product()
+		if err != nil {
+			return b, err
+		}
+		if a, err = op(a, b); err != nil {
+			return a, err
+		}
+	}
+}
+
+// product := ["/"] juxt { ("*" | "/" | "per") juxt }
+func (e *evaluator) product() (unit.Value, error) {
+	var a unit.Value
+	var err error
+	if e.peek() == '/' {
+		// A leading "/", as in "/s", divides 1.
+		a = unit.Value{S: 1}
+	} else if a, err = e.juxt(); err != nil {
+		return a, err
+	}
+	for {
+		var div bool
+		switch {
+		case e.peek() == '*' && !strings.HasPrefix(e.s[e.pos:], "**"):
+			e.pos++
+		case e.accept("/"):
+			div = true
+		case e.acceptWord("per"):
+			div = true
+		default:
+			return a, nil
+		}
+		b, err := e.juxt()
+		if err != nil {
+			return b, err
+		}
+		if div {
+			a = a.Div(b)
+		} else {
+			a = a.Mul(b)
+		}
+	}
+}
+
+// juxt := unary { power }
+func (e *evaluator) juxt() (unit.Value, error) {
+	a, err := e.unary()
+	if err != nil {
+		return a, err
+	}
+	for e.startsOperand() {
+		b, err := e.power()
+		if err != nil {
+			return b, err
+		}
+		a = a.Mul(b)
+	}
+	return a, nil
+}
+
+// startsOperand returns true if an operand, rather than an operator or
+// the end of the expression, is next.
+func (e *evaluator) startsOperand() bool {
+	switch c := e.peek(); c {
+	case 0, '+', '-', '*', '/', '|', '^', ')', ';', ',':
+		return false
+	}
+	save := e.pos
+	per := e.acceptWord("per")
+	e.pos = save
+	return !per
+}
+
+// unary := "-" unary | power
+func (e *evaluator) unary() (unit.Value, error) {
+	if e.accept("-") {
+		v, err := e.unary()
+		return v.MulN(-1), err
+	}
+	return e.power()
+}
+
+// power := numdiv [ ("^" | "**") unary ]
+func (e *evaluator) power() (unit.Value, error) {
+	a, err := e.numdiv()
+	if err != nil {
+		return a, err
+	}
+	if !e.accept("^") && !e.accept("**") {
+		return a, nil
+	}
+	p, err := e.unary()
+	if err != nil {
+		return p, err
+	}
+	return pow(a, p)
+}
+
+// pow raises a to the power p, which must be a number.  Fractional powers
+// are approximated as rational powers with small denominators.
+func pow(a, p unit.Value) (unit.Value, error) {
+	x, err := number(p)
+	if err != nil {
+		return unit.Value{}, fmt.Errorf("exponent %v: %w", p, err)
+	}
+	if x == math.Trunc(x) && math.Abs(x) < 1<<16 {
+		return a.Pow(int(x)), nil
+	}
+	if a.U.Empty() {
+		return unit.Value{S: math.Pow(a.S, x)}, nil
+	}
+	for den := 2; den <= maxExpDenom; den++ {
+		if num := math.Round(x * float64(den)); math.Abs(num/float64(den)-x) < 1e-9 {
+			return a.PowRat(int(num), den)
+		}
+	}
+	return unit.Value{}, fmt.Errorf("cannot raise %v to the power %v", a.U, x)
+}
+
+// number returns v as a plain number, if it has no dimensions.
+func number(v unit.Value) (float64, error) {
+	r := v.Reduce().DropDimensionless()
+	if !r.U.Empty() {
+		return 0, fmt.Errorf("%q is not a number", v.U)
+	}
+	return r.S, nil
+}
+
+// numdiv := primary { "|" primary }
+func (e *evaluator) numdiv() (unit.Value, error) {
+	a, err := e.primary()
+	if err != nil {
+		return a, err
+	}
+	for e.accept("|") {
+		b, err := e.primary()
+		if err != nil {
+			return b, err
+		}
+		a = a.Div(b)
+	}
+	return a, nil
+}
+
+// primary := number | "(" sum ")" | ["~"] name "(" sum ")" | name
+func (e *evaluator) primary() (unit.Value, error) {
+	c := e.peek()
+	switch {
+	case c == 0:
+		return unit.Value{}, fmt.Errorf("unexpected end of %q", e.s)
+	case c == '(':
+		e.pos++
+		v, err := e.sum()
+		if err != nil {
+			return v, err
+		}
+		if !e.accept(")") {
+			return v, fmt.Errorf("missing ) in %q", e.s)
+		}
+		return v, nil
+	case c == '.' || c >= '0' && c <= '9':
+		return e.number()
+	}
+	inverse := e.accept("~")
+	name := e.name()
+	if name == "" {
+		return unit.Value{}, fmt.Errorf("unexpected %q in %q", e.s[e.pos:], e.s)
+	}
+	if e.pos < len(e.s) && e.s[e.pos] == '(' {
+		e.pos++
+		arg, err := e.sum()
+		if err != nil {
+			return arg, err
+		}
+		if !e.accept(")") {
+			return arg, fmt.Errorf("missing ) in %q", e.s)
+		}
+		return e.call(name, inverse, arg)
+	}
+	if inverse {
+		return unit.Value{}, fmt.Errorf("~%s is not a function call", name)
+	}
+	if v, ok := e.vars[name]; ok {
+		return v, nil
+	}
+	v, err := e.d.lookup(name)
+	if err == nil {
+		return v, nil
+	}
+	// A name ending in a digit, such as "m2", is a unit raised to that
+	// power.
+	if i := len(name) - 1; i > 0 && name[i] >= '2' && name[i] <= '9' {
+		if b, berr := e.d.lookup(name[:i]); berr == nil {
+			return b.Pow(int(name[i] - '0')), nil
+		}
+	}
+	return v, err
+}
+
+// name scans a unit or function name.
+func (e *evaluator) name() string {
+	start := e.pos
+	for e.pos < len(e.s) {
+		r, n := utf8.DecodeRuneInString(e.s[e.pos:])
+		if unicode.IsSpace(r) || strings.ContainsRune(operators, r) {
+			break
+		}
+		e.pos += n
+	}
+	return e.s[start:e.pos]
+}
+
+// number scans a number, as in "12", "1.5" or "6.02e23".
+func (e *evaluator) number() (unit.Value, error) {
+	start := e.pos
+	digits := func() {
+		for e.pos < len(e.s) && e.s[e.pos] >= '0' && e.s[e.pos] <= '9' {
+			e.pos++
+		}
+	}
+	digits()
+	if e.pos < len(e.s) && e.s[e.pos] == '.' {
+		e.pos++
+		digits()
+	}
+	if e.pos < len(e.s) && (e.s[e.pos] == 'e' || e.s[e.pos] == 'E') {
+		save := e.pos
+		e.pos++
+		if e.pos < len(e.s) && (e.s[e.pos] == '+' || e.s[e.pos] == '-') {
+			e.pos++
+		}
+		if e.pos == len(e.s) || e.s[e.pos] < '0' || e.s[e.pos] > '9' {
+			// Not an exponent, but a unit such as "e" or "erg".
+			e.pos = save
+		}
+		digits()
+	}
+	f, err := strconv.ParseFloat(e.s[start:e.pos], 64)
+	if err != nil {
+		return unit.Value{}, fmt.Errorf("invalid number %q", e.s[start:e.pos])
+	}
+	return unit.Value{S: f}, nil
+}
+
+// call calls the built-in or defined function name, or its inverse.
+func (e *evaluator) call(name string, inverse bool, arg unit.Value) (unit.Value, error) {
+	if f := e.d.funcs[name]; f != nil {
+		if inverse {
+			return f.Inverse(arg)
+		}
+		return f.Call(arg)
+	}
+	fn := builtins[name]
+	if fn == nil || inverse {
+		return unit.Value{}, fmt.Errorf("unknown function %q", name)
+	}
+	switch name {
+	case "sqrt":
+		return arg.Sqrt()
+	case "cuberoot":
+		return arg.Root(3)
+	}
+	x, err := number(arg)
+	if err != nil {
+		return unit.Value{}, fmt.Errorf("%s: %w", name, err)
+	}
+	return unit.Value{S: fn(x)}, nil
+}
@@ -0,0 +1,235 @@
+package unitdef
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dnesting/unit"
+)
+
+// Func is a nonlinear unit defined as a function, such as the tempF(x)
+// used for temperatures in degrees Fahrenheit.
+type Func struct {
+	Name  string
+	Param string
+
+	fwd, inv        string // expressions for the function and its inverse
+	inText, outText string // expressions for the units, if given
+	in, out         *unit.Value
+	domain, rng     interval
+	d               *Defs
+	file            string
+	line            int
+}
+
+// interval is a range of numbers, with open or closed ends.
+type interval struct {
+	set            bool
+	lo, hi         float64
+	loOpen, hiOpen bool
+}
+
+func (i interval) contains(x float64) bool {
+	if !i.set {
+		return true
+	}
+	if x < i.lo || x > i.hi {
+		return false
+	}
+	return !(i.loOpen && x == i.lo || i.hiOpen && x == i.hi)
+}
+
+func (i interval) String() string {
+	var b strings.Builder
+	b.WriteByte("[("[btoi(i.loOpen)])
+	if !math.IsInf(i.lo, -1) {
+		b.WriteString(strconv.FormatFloat(i.lo, 'g', -1, 64))
+	}
+	b.WriteByte(',')
+	if !math.IsInf(i.hi, 1) {
+		b.WriteString(strconv.FormatFloat(i.hi, 'g', -1, 64))
+	}
+	b.WriteByte("])"[btoi(i.hiOpen)])
+	return b.String()
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// parseInterval parses an interval such as "[0,1]", "(0,)" or "[-273.15,)".
+// A missing bound is unbounded.
+func parseInterval(s string) (interval, error) {
+	i := interval{set: true, lo: math.Inf(-1), hi: math.Inf(1)}
+	if len(s) < 2 || !strings.ContainsAny(s[:1], "[(") || !strings.ContainsAny(s[len(s)-1:], "])") {
+		return i, fmt.Errorf("invalid interval %q", s)
+	}
+	parts := strings.Split(s[1:len(s)-1], ",")
+	if len(parts) != 2 {
+		return i, fmt.Errorf("invalid interval %q", s)
+	}
+	i.loOpen, i.hiOpen = s[0] == '(', s[len(s)-1] == ')'
+	for k, p := range []*float64{&i.lo, &i.hi} {
+		if t := strings.TrimSpace(parts[k]); t != "" {
+			f, err := strconv.ParseFloat(t, 64)
+			if err != nil {
+				return i, fmt.Errorf("invalid interval %q", s)
+			}
+			*p = f
+		}
+	}
+	return i, nil
+}
+
+// parseFunc parses a function definition such as
+//
+//	tempC(x) units=[1;K] domain=[-273.15,) x K + stdtemp ; (tempC+(-stdtemp))/K
+//
+// giving the function's parameter, the units of its argument and result,
+// the domain and range of the numbers they represent, and expressions for
+// the function and its inverse, in which the function's name stands for
+// its result.
+func parseFunc(d *Defs, text string) (*Func, error) {
+	open := strings.IndexByte(text, '(')
+	close := strings.IndexByte(text, ')')
+	if close < open {
+		return nil, fmt.Errorf("invalid function definition %q", text)
+	}
+	f := &Func{
+		Name:  text[:open],
+		Param: strings.TrimSpace(text[open+1 : close]),
+		d:     d,
+	}
+	if !validName(f.Name) || !validName(f.Param) {
+		return nil, fmt.Errorf("invalid function definition %q", text)
+	}
+	rest := strings.TrimSpace(text[close+1:])
+	for {
+		var key string
+		for _, k := range []string{"units=", "domain=", "range=", "noerror"} {
+			if strings.HasPrefix(rest, k) {
+				key = k
+			}
+		}
+		if key == "" {
+			break
+		}
+		rest = rest[len(key):]
+		if key == "noerror" {
+			// Rounding errors in the inverse are never reported.
+			rest = strings.TrimSpace(rest)
+			continue
+		}
+		end := strings.IndexAny(rest, "])")
+		if end < 0 {
+			return nil, fmt.Errorf("%s: unterminated %s", f.Name, key)
+		}
+		val := rest[:end+1]
+		rest = strings.TrimSpace(rest[end+1:])
+		var err error
+		switch key {
+		case "units=":
+			parts := strings.Split(strings.Trim(val, "[]"), ";")
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("%s: invalid units %q", f.Name, val)
+			}
+			f.inText, f.outText = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		case "domain=":
+			f.domain, err = parseInterval(val)
+		case "range=":
+			f.rng, err = parseInterval(val)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+	}
+	f.fwd, f.inv = rest, ""
+	if i := strings.IndexByte(rest, ';'); i >= 0 {
+		f.fwd, f.inv = strings.TrimSpace(rest[:i]), strings.TrimSpace(rest[i+1:])
+	}
+	if f.fwd == "" {
+		return nil, fmt.Errorf("%s: missing definition", f.Name)
+	}
+	return f, nil
+}
+
+// resolve evaluates the units of f's argument and result, once all units
+// are defined.
+func (f *Func) resolve() error {
+	for _, x := range []struct {
+		text string
+		v    **unit.Value
+	}{{f.inText, &f.in}, {f.outText, &f.out}} {
+		if x.text == "" {
+			continue
+		}
+		v, err := f.d.eval(x.text, nil)
+		if err != nil {
+			return fmt.Errorf("%s: units: %w", f.Name, err)
+		}
+		*x.v = &v
+	}
+	return nil
+}
+
+// check returns the number v represents in the units want, or an error if
+// v does not conform to them or is out of the interval valid.
+func (f *Func) check(v unit.Value, want *unit.Value, valid interval, what string) error {
+	if want != nil {
+		x, err := number(v.Div(*want))
+		if err != nil {
+			return fmt.Errorf("%s: %s %v does not conform to %q", f.Name, what, v, want.U)
+		}
+		if !valid.contains(x) {
+			return fmt.Errorf("%s: %s %v is outside %v", f.Name, what, v, valid)
+		}
+	} else if valid.set {
+		x, err := number(v)
+		if err != nil || !valid.contains(x) {
+			return fmt.Errorf("%s: %s %v is outside %v", f.Name, what, v, valid)
+		}
+	}
+	return nil
+}
+
+// Call returns the result of f for the argument x.
+func (f *Func) Call(x unit.Value) (unit.Value, error) {
+	if err := f.check(x, f.in, f.domain, "argument"); err != nil {
+		return unit.Value{}, err
+	}
+	r, err := f.d.eval(f.fwd, map[string]unit.Value{f.Param: x})
+	if err != nil {
+		return unit.Value{}, fmt.Errorf("%s: %w", f.Name, err)
+	}
+	return plain(r), nil
+}
+
+// Inverse returns the argument for which f gives the result y.
+func (f *Func) Inverse(y unit.Value) (unit.Value, error) {
+	if f.inv == "" {
+		return unit.Value{}, fmt.Errorf("%s has no inverse", f.Name)
+	}
+	if err := f.check(y, f.out, f.rng, "result"); err != nil {
+		return unit.Value{}, err
+	}
+	r, err := f.d.eval(f.inv, map[string]unit.Value{f.Name: y})
+	if err != nil {
+		return unit.Value{}, fmt.Errorf("~%s: %w", f.Name, err)
+	}
+	return plain(r), nil
+}
+
+// plain returns v as a plain number if it has no dimensions, so that
+// results such as "(tempF+(-stdtemp))/degF + 32" are not left in units
+// like "K/degF".
+func plain(v unit.Value) unit.Value {
+	if r := v.Reduce(); r.U.Empty() {
+		return r
+	}
+	return v
+}
@@ -0,0 +1,585 @@
+// Package unitdef loads unit definitions written in the format of GNU
+// Units' definitions.units file into a unit.Registry.
+//
+// Primitive units ("m !"), dimensionless primitives ("radian
+// !dimensionless"), prefixes ("kilo- 1e3"), derived units ("foot 12
+// inch"), nonlinear functions ("tempC(x) units=[1;K] x K + stdtemp ;
+// (tempC+(-stdtemp))/K"), comments, continued lines and the !include,
+// !locale, !var, !varnot, !set and !utf8 directives are supported.  As in
+// GNU Units, definitions may refer to units defined later in the file.
+// Constructs that are not supported, such as piecewise linear tables and
+// unit lists, and definitions that cannot be evaluated, are skipped and
+// reported as Diagnostics.
+package unitdef
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dnesting/unit"
+	"github.com/dnesting/unit/si"
+)
+
+// Diagnostic describes a problem with a line of a definitions file.
+type Diagnostic struct {
+	File string
+	Line int
+	Msg  string
+}
+
+func (d Diagnostic) Error() string {
+	return fmt.Sprintf("%s:%d: %s", d.File, d.Line, d.Msg)
+}
+
+// Defs holds a set of loaded unit definitions.
+type Defs struct {
+	// Registry holds the units and prefixes defined, by name, for use
+	// with unit.Parse.
+	Registry *unit.Registry
+	// Diagnostics lists the lines that could not be loaded, in the
+	// order they were found.
+	Diagnostics []Diagnostic
+
+	base     *unit.Registry
+	defs     map[string]*definition // units, by name
+	prefixes map[string]*definition // prefixes, by name without the "-"
+	funcs    map[string]*Func
+	order    []*definition // units and prefixes, in the order defined
+
+	units   map[string]unit.Value // one of each unit, or its number
+	pvals   map[string]float64
+	pfuncs  map[string]func(unit.Maker) unit.Maker
+	pnames  []string // prefix names, longest first
+	failed  map[*definition]bool
+	pending map[*definition]bool // being evaluated
+}
+
+// definition is a unit or prefix definition that has not yet been
+// evaluated.
+type definition struct {
+	name, text string
+	prefix     bool
+	file       string
+	line       int
+}
+
+// Option configures how definitions are loaded.
+type Option func(l *loader)
+
+// WithLocale sets the locale used to select !locale sections.  By default,
+// "en_US" is used.
+func WithLocale(locale string) Option {
+	return func(l *loader) { l.locale = locale }
+}
+
+// WithVar sets a variable used to select !var and !varnot sections, taking
+// precedence over any value given to it with !set.
+func WithVar(name, value string) Option {
+	return func(l *loader) { l.vars[name] = value }
+}
+
+// WithBase specifies a registry whose units are used in place of primitive
+// units of the same name, so that loaded units are compatible with those
+// already in use.  By default, si.Registry is used.  If base is nil,
+// primitive units are always created anew.
+func WithBase(base *unit.Registry) Option {
+	return func(l *loader) { l.defs.base = base }
+}
+
+// loader reads definitions files, tracking the state of conditional
+// sections.
+type loader struct {
+	defs   *Defs
+	locale string
+	vars   map[string]string
+	depth  int
+}
+
+// frame is an open conditional section.
+type frame struct {
+	kind   string // "locale", "var" or "utf8"
+	active bool
+	line   int // the line that opened the section
+}
+
+// maxIncludeDepth limits nested !include directives, to catch cycles.
+const maxIncludeDepth = 16
+
+func newLoader(opts []Option) *loader {
+	l := &loader{
+		defs: &Defs{
+			Registry: unit.NewRegistry("units", nil),
+			base:     &si.Registry,
+			defs:     make(map[string]*definition),
+			prefixes: make(map[string]*definition),
+			funcs:    make(map[string]*Func),
+			units:    make(map[string]unit.Value),
+			pvals:    make(map[string]float64),
+			pfuncs:   make(map[string]func(unit.Maker) unit.Maker),
+			failed:   make(map[*definition]bool),
+			pending:  make(map[*definition]bool),
+		},
+		locale: "en_US",
+		vars:   make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Paths lists the locations searched by Definitions for a definitions
+// file, after the file named by the UNITSFILE environment variable.
+var Paths = []string{
+	"/usr/share/units/definitions.units",
+	"/usr/local/share/units/definitions.units",
+	"/opt/homebrew/share/units/definitions.units",
+	"/usr/share/misc/units.lib",
+}
+
+// Definitions loads the system's GNU Units definitions file, named by the
+// UNITSFILE environment variable or found in one of Paths.
+func Definitions(opts ...Option) (*Defs, error) {
+	paths := Paths
+	if f := os.Getenv("UNITSFILE"); f != "" {
+		paths = append([]string{f}, paths...)
+	}
+	for _, p := range paths {
+		if _, err := os.Stat(p); err == nil {
+			return Load(p, opts...)
+		}
+	}
+	return nil, errors.New("unitdef: no definitions file found")
+}
+
+// Load reads definitions from the named file.  Files named by !include
+// directives are found relative to the directory of the file including
+// them.  The returned error reports only failures to read the file; see
+// Defs.Diagnostics for problems with its contents.
+func Load(filename string, opts ...Option) (*Defs, error) {
+	l := newLoader(opts)
+	if err := l.include(filename); err != nil {
+		return nil, err
+	}
+	return l.finish(), nil
+}
+
+// Read reads definitions from r.  Name is used in diagnostics, and to find
+// files named by !include directives.
+func Read(r io.Reader, name string, opts ...Option) (*Defs, error) {
+	l := newLoader(opts)
+	if err := l.read(r, name); err != nil {
+		return nil, err
+	}
+	return l.finish(), nil
+}
+
+func (l *loader) include(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return l.read(f, filename)
+}
+
+func (l *loader) diag(file string, line int, format string, args ...interface{}) {
+	l.defs.Diagnostics = append(l.defs.Diagnostics, Diagnostic{file, line, fmt.Sprintf(format, args...)})
+}
+
+func (l *loader) read(r io.Reader, name string) error {
+	var stack []frame
+	active := func() bool {
+		for _, f := range stack {
+			if !f.active {
+				return false
+			}
+		}
+		return true
+	}
+	sc := bufio.NewScanner(r)
+	var n, start int
+	var cont strings.Builder
+	for sc.Scan() {
+		n++
+		line := sc.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		// A trailing backslash continues the line on the next.
+		if strings.HasSuffix(line, "\\") {
+			if cont.Len() == 0 {
+				start = n
+			}
+			cont.WriteString(strings.TrimSuffix(line, "\\"))
+			cont.WriteByte(' ')
+			continue
+		}
+		lineNo := n
+		if cont.Len() > 0 {
+			cont.WriteString(line)
+			line, lineNo = cont.String(), start
+			cont.Reset()
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if line[0] == '!' {
+			f := strings.Fields(line)
+			switch f[0] {
+			case "!locale":
+				if len(f) != 2 {
+					l.diag(name, lineNo, "!locale requires a locale")
+				}
+				stack = append(stack, frame{"locale", len(f) > 1 && f[1] == l.locale, lineNo})
+			case "!var", "!varnot":
+				if len(f) < 3 {
+					l.diag(name, lineNo, "%s requires a variable and values", f[0])
+				}
+				match := false
+				if len(f) >= 3 {
+					if v, ok := l.vars[f[1]]; ok {
+						for _, x := range f[2:] {
+							match = match || x == v
+						}
+					}
+				}
+				stack = append(stack, frame{"var", match == (f[0] == "!var"), lineNo})
+			case "!utf8":
+				stack = append(stack, frame{"utf8", true, lineNo})
+			case "!endlocale", "!endvar", "!endutf8":
+				kind := strings.TrimPrefix(f[0], "!end")
+				if len(stack) == 0 || stack[len(stack)-1].kind != kind {
+					l.diag(name, lineNo, "%s without matching !%s", f[0], kind)
+					continue
+				}
+				stack = stack[:len(stack)-1]
+			case "!set":
+				if len(f) != 3 {
+					l.diag(name, lineNo, "!set requires a variable and a value")
+				} else if _, ok := l.vars[f[1]]; !ok && active() {
+					l.vars[f[1]] = f[2]
+				}
+			case "!include":
+				if !active() {
+					continue
+				}
+				if len(f) != 2 {
+					l.diag(name, lineNo, "!include requires a file name")
+					continue
+				}
+				if l.depth >= maxIncludeDepth {
+					l.diag(name, lineNo, "!include nested too deeply")
+					continue
+				}
+				file := f[1]
+				if !filepath.IsAbs(file) {
+					file = filepath.Join(filepath.Dir(name), file)
+				}
+				l.depth++
+				err := l.include(file)
+				l.depth--
+				if err != nil {
+					l.diag(name, lineNo, "%v", err)
+				}
+			case "!message", "!prompt":
+				// Messages for interactive use are ignored.
+			default:
+				if active() {
+					l.diag(name, lineNo, "unsupported directive %s", f[0])
+				}
+			}
+			continue
+		}
+		if active() {
+			l.define(name, lineNo, line)
+		}
+	}
+	for _, f := range stack {
+		l.diag(name, f.line, "unterminated !%s section", f.kind)
+	}
+	return sc.Err()
+}
+
+// define records the definition on a line for evaluation once all
+// definitions are loaded.
+func (l *loader) define(file string, line int, text string) {
+	name, rest := text, ""
+	if i := strings.IndexAny(text, " \t"); i >= 0 {
+		name, rest = text[:i], strings.TrimSpace(text[i+1:])
+	}
+	d := l.defs
+	switch {
+	case strings.ContainsRune(name, '('):
+		f, err := parseFunc(d, text)
+		if err != nil {
+			l.diag(file, line, "%v", err)
+			return
+		}
+		if d.funcs[f.Name] != nil {
+			l.diag(file, line, "redefinition of function %q ignored", f.Name)
+			return
+		}
+		f.file, f.line = file, line
+		d.funcs[f.Name] = f
+		return
+	case strings.ContainsRune(name, '['):
+		l.diag(file, line, "unsupported table definition %q", name)
+		return
+	case rest == "":
+		l.diag(file, line, "missing definition for %q", name)
+		return
+	}
+	def := &definition{name: name, text: rest, file: file, line: line}
+	if strings.HasSuffix(name, "-") {
+		def.name, def.prefix = strings.TrimSuffix(name, "-"), true
+		if d.prefixes[def.name] != nil {
+			l.diag(file, line, "redefinition of prefix %q ignored", def.name)
+			return
+		}
+		d.prefixes[def.name] = def
+	} else {
+		if !validName(name) {
+			l.diag(file, line, "invalid unit name %q", name)
+			return
+		}
+		if d.defs[name] != nil {
+			l.diag(file, line, "redefinition of unit %q ignored", name)
+			return
+		}
+		d.defs[name] = def
+	}
+	d.order = append(d.order, def)
+}
+
+// validName returns true if name may be used as a unit name.
+func validName(name string) bool {
+	if name == "" || strings.ContainsAny(name[:1], "0123456789.") {
+		return false
+	}
+	return !strings.ContainsAny(name, operators)
+}
+
+// finish evaluates every definition loaded, reporting those that fail.
+func (l *loader) finish() *Defs {
+	d := l.defs
+	for name := range d.prefixes {
+		d.pnames = append(d.pnames, name)
+	}
+	sort.Slice(d.pnames, func(i, j int) bool {
+		if a, b := d.pnames[i], d.pnames[j]; len(a) != len(b) {
+			return len(a) > len(b)
+		}
+		return d.pnames[i] < d.pnames[j]
+	})
+	for _, def := range d.order {
+		var err error
+		if def.prefix {
+			_, err = d.prefix(def.name)
+		} else {
+			_, _, err = d.unit(def.name)
+		}
+		if err != nil && !d.failed[def] {
+			d.fail(def, err)
+		}
+	}
+	names := make([]string, 0, len(d.funcs))
+	for name := range d.funcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := d.funcs[name]
+		if err := f.resolve(); err != nil {
+			d.Diagnostics = append(d.Diagnostics, Diagnostic{f.file, f.line, err.Error()})
+		}
+	}
+	return d
+}
+
+func (d *Defs) fail(def *definition, err error) {
+	d.failed[def] = true
+	d.Diagnostics = append(d.Diagnostics, Diagnostic{def.file, def.line, err.Error()})
+}
+
+// evaluate evaluates the definition def, guarding against cycles.
+func (d *Defs) evaluate(def *definition) (unit.Value, error) {
+	if d.failed[def] {
+		return unit.Value{}, fmt.Errorf("%q is not defined correctly", def.name)
+	}
+	if d.pending[def] {
+		return unit.Value{}, fmt.Errorf("definition of %q refers to itself", def.name)
+	}
+	d.pending[def] = true
+	defer delete(d.pending, def)
+	v, err := d.eval(def.text, nil)
+	if err != nil {
+		d.fail(def, fmt.Errorf("%s: %w", def.name, err))
+		return unit.Value{}, fmt.Errorf("%q is not defined correctly", def.name)
+	}
+	return v, nil
+}
+
+// unit returns one of the unit defined as name, evaluating its definition
+// if necessary.  Units defined as plain numbers, such as "dozen 12", are
+// numbers rather than units, as in GNU Units.  Returns false if name is
+// not defined.
+func (d *Defs) unit(name string) (v unit.Value, ok bool, err error) {
+	if v, ok := d.units[name]; ok {
+		return v, true, nil
+	}
+	def := d.defs[name]
+	if def == nil {
+		return unit.Value{}, false, nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s: %v", name, r)
+		}
+	}()
+	var m unit.Maker
+	switch def.text {
+	case "!":
+		if b := d.base.Find(name); b != nil && b.Unit() != nil && b.Unit().Symbol() == name {
+			m = d.Registry.Register(b)
+		} else {
+			m = d.Registry.Primitive(name)
+		}
+	case "!dimensionless":
+		m = d.Registry.Dimensionless(name)
+	default:
+		if strings.HasPrefix(def.text, "!") {
+			if !d.failed[def] {
+				d.fail(def, fmt.Errorf("unsupported primitive definition %q", def.text))
+			}
+			return unit.Value{}, true, fmt.Errorf("%q is not defined correctly", name)
+		}
+		v, err := d.evaluate(def)
+		if err != nil {
+			return unit.Value{}, true, err
+		}
+		if r := v.Reduce(); r.U.Empty() {
+			d.units[name] = r
+			return r, true, nil
+		}
+		m = d.Registry.Derive(name, v)
+	}
+	d.units[name] = m(1)
+	return m(1), true, nil
+}
+
+// prefix returns the multiplier of the prefix named name, evaluating its
+// definition if necessary.
+func (d *Defs) prefix(name string) (float64, error) {
+	if v, ok := d.pvals[name]; ok {
+		return v, nil
+	}
+	def := d.prefixes[name]
+	if def == nil {
+		return 0, fmt.Errorf("unknown prefix %q", name)
+	}
+	v, err := d.evaluate(def)
+	if err != nil {
+		return 0, err
+	}
+	if v = v.Reduce(); !v.U.Empty() {
+		d.fail(def, fmt.Errorf("prefix %q must be a number, got %v", name, v))
+		return 0, fmt.Errorf("%q is not defined correctly", name)
+	}
+	d.pvals[name] = v.S
+	d.pfuncs[name] = d.Registry.Prefix(name, v.S)
+	return v.S, nil
+}
+
+// lookup finds the unit or prefix named name, allowing for plurals and
+// prefixed units as GNU Units does, and returns it as a value.
+func (d *Defs) lookup(name string) (unit.Value, error) {
+	if v, ok, err := d.singular(name); ok {
+		return v, err
+	}
+	if _, ok := d.prefixes[name]; ok {
+		f, err := d.prefix(name)
+		return unit.Value{S: f}, err
+	}
+	for _, p := range d.pnames {
+		rest := strings.TrimPrefix(name, p)
+		if rest == name || rest == "" {
+			continue
+		}
+		v, ok, err := d.singular(rest)
+		if !ok || err != nil {
+			continue
+		}
+		f, err := d.prefix(p)
+		if err != nil {
+			return unit.Value{}, err
+		}
+		if m := d.maker(v); m != nil {
+			return d.pfuncs[p](m)(1), nil
+		}
+		return v.MulN(f), nil
+	}
+	return unit.Value{}, fmt.Errorf("unknown unit %q", name)
+}
+
+// singular finds the unit named name, or if there is none, the unit name
+// may be the plural of.
+func (d *Defs) singular(name string) (unit.Value, bool, error) {
+	if v, ok, err := d.unit(name); ok {
+		return v, ok, err
+	}
+	for _, p := range [][2]string{{"ies", "y"}, {"es", ""}, {"s", ""}} {
+		if s := strings.TrimSuffix(name, p[0]); s != name && len(s) > 1 {
+			if v, ok, err := d.unit(s + p[1]); ok {
+				return v, ok, err
+			}
+		}
+	}
+	return unit.Value{}, false, nil
+}
+
+// maker returns the Maker for v, if it is one of a single unit.
+func (d *Defs) maker(v unit.Value) unit.Maker {
+	if v.S != 1 || len(v.U.N) != 1 || len(v.U.D) != 0 {
+		return nil
+	}
+	return v.U.Make
+}
+
+// Find returns the unit named name, allowing for plurals and prefixes, or
+// nil if there is none.
+func (d *Defs) Find(name string) unit.Maker {
+	v, err := d.lookup(name)
+	if err != nil {
+		return nil
+	}
+	return d.maker(v)
+}
+
+// Func returns the nonlinear function named name, or nil if there is none.
+func (d *Defs) Func(name string) *Func {
+	return d.funcs[name]
+}
+
+// Parse evaluates the expression s, as in "kg m/s^2", "3 ft + 2 in" or
+// "tempF(75)", using the units defined.
+func (d *Defs) Parse(s string) (unit.Value, error) {
+	return d.eval(s, nil)
+}
+
+// Must returns a Maker for the units given by the expression s, such as
+// "m/s", as evaluated by Parse.  It panics if s cannot be evaluated.
+func (d *Defs) Must(s string) unit.Maker {
+	v, err := d.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v.MulN
+}
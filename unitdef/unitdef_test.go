@@ -0,0 +1,300 @@
+package unitdef_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dnesting/unit"
+	"github.com/dnesting/unit/si"
+	"github.com/dnesting/unit/unitdef"
+)
+
+func load(t *testing.T, opts ...unitdef.Option) *unitdef.Defs {
+	t.Helper()
+	d, err := unitdef.Load("testdata/sample.units", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestDiagnostics(t *testing.T) {
+	d := load(t)
+	var got []string
+	for _, diag := range d.Diagnostics {
+		got = append(got, diag.Error())
+	}
+	expected := []string{
+		`testdata/sample.units:85: unsupported table definition "gasmark[degR]"`,
+		`testdata/sample.units:89: redefinition of unit "meter" ignored`,
+		`testdata/sample.units:90: unsupported directive !unitlist`,
+		`testdata/sample.units:86: bogus: unknown unit "fathom"`,
+		`testdata/sample.units:88: loopb: definition of "loopa" refers to itself`,
+		`testdata/sample.units:87: loopa: "loopb" is not defined correctly`,
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected diagnostics:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestParse(t *testing.T) {
+	d := load(t)
+	for _, x := range []struct {
+		expr     string
+		expected unit.Value
+	}{
+		{"m/s", si.Metre.Div(si.Second)(1)},
+		{"kg m/s^2", si.Newton(1)},
+		{"N", si.Newton(1)},
+		{"3 ft + 2 in", si.Metre(0.9652)},
+		{"2 feet", unit.Value{}}, // not defined
+		{"5 km", si.Kilo(si.Metre)(5)},
+		{"2 kilometers", si.Kilo(si.Metre)(2)},
+		{"cm3", si.Centi(si.Metre).Pow(3)(1)},
+		{"hours", si.Second(3600)},
+		{"2 hr + 30 min", si.Second(9000)},
+		{"1|8 mile", si.Metre(201.168)},
+		{"/s", unit.Scalar(1).Div(si.Second)(1)},
+		{"hertz", unit.Scalar(1).Div(si.Second)(1)},
+		{"m per s", si.Metre.Div(si.Second)(1)},
+		{"kg m / s s", si.Kilogram.Mul(si.Metre).Div(si.Second.Pow(2))(1)},
+		{"2^3^2", unit.Value{S: 512}},
+		{"-2 m", si.Metre(-2)},
+		{"sqrt(4 m^2)", si.Metre(2)},
+		{"(m^3)^1|3", si.Metre(1)},
+		{"sin(90 deg)", unit.Value{S: 1}},
+		{"dozen", unit.Value{}}, // not defined
+		{"pi", unit.Value{S: 3.14159265358979323846}},
+		{"lbf", si.Newton(4.4482216152605)},
+		{"knots", si.Metre.Div(si.Second)(0.514444444444)},
+		{"gallon", si.Liter(3.785411784)},
+		{"tempC(100)", si.Kelvin(373.15)},
+		{"~tempC(300 K)", unit.Value{S: 26.85}},
+		{"tempF(212)", si.Kelvin(373.15)},
+		{"~tempF(tempC(100))", unit.Value{S: 212}},
+		{"tempC(-300)", unit.Value{}}, // outside domain
+		{"~tempC(3 m)", unit.Value{}}, // not a temperature
+		{"bogus", unit.Value{}},       // defined incorrectly
+		{"2 m +", unit.Value{}},
+		{"(2 m", unit.Value{}},
+		{"m + s", unit.Value{}},
+	} {
+		v, err := d.Parse(x.expr)
+		if x.expected.S == 0 {
+			if err == nil {
+				t.Errorf("%q should fail, got %v", x.expr, v)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", x.expr, err)
+			continue
+		}
+		if !v.ApproxRel(x.expected, 1e-9) {
+			t.Errorf("%q should give %v, got %v", x.expr, x.expected, v)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	d := load(t)
+	for _, x := range []struct {
+		name     string
+		expected string
+	}{
+		{"m", "m"},
+		{"feet", ""},
+		{"foot", "foot"},
+		{"miles", "mile"},
+		{"km", "km"},
+		{"pi", ""},
+		{"nothing", ""},
+	} {
+		m := d.Find(x.name)
+		if x.expected == "" {
+			if m != nil {
+				t.Errorf("Find(%q) should give nil, got %v", x.name, m)
+			}
+			continue
+		}
+		if m == nil || m.Unit().Symbol() != x.expected {
+			t.Errorf("Find(%q) should give %q, got %v", x.name, x.expected, m)
+		}
+	}
+	if m := d.Find("s"); m == nil || !m.Unit().Equal(si.Second.Unit()) {
+		t.Errorf("primitive s should be si.Second, got %v", m)
+	}
+	if m := d.Registry.Find("km"); m == nil || !m(1).Equal(si.Kilo(si.Metre)(1)) {
+		t.Errorf("Registry should find km, got %v", m)
+	}
+	if f := d.Func("tempF"); f == nil || f.Param != "x" {
+		t.Errorf("Func(tempF) should be defined, got %v", f)
+	}
+}
+
+func TestConditionals(t *testing.T) {
+	for _, x := range []struct {
+		opts       []unitdef.Option
+		gallon     unit.Value
+		unitsystem float64
+	}{
+		{nil, si.Liter(3.785411784), 2},
+		{[]unitdef.Option{unitdef.WithLocale("en_GB")}, si.Liter(4.54609), 2},
+		{[]unitdef.Option{unitdef.WithVar("UNITS_SYSTEM", "si")}, si.Liter(3.785411784), 1},
+	} {
+		d := load(t, x.opts...)
+		if v, err := d.Parse("gallon"); err != nil || !v.ApproxRel(x.gallon, 1e-9) {
+			t.Errorf("gallon should be %v, got %v (%v)", x.gallon, v, err)
+		}
+		if v, err := d.Parse("unitsystem"); err != nil || v.S != x.unitsystem {
+			t.Errorf("unitsystem should be %v, got %v (%v)", x.unitsystem, v, err)
+		}
+	}
+}
+
+func TestBase(t *testing.T) {
+	d := load(t, unitdef.WithBase(nil))
+	if m := d.Find("s"); m == nil || m.Unit().Equal(si.Second.Unit()) {
+		t.Errorf("without a base, s should be a new primitive, got %v", m)
+	}
+	if v, err := d.Parse("3 ft + 2 in"); err != nil || !v.ApproxRel(d.Must("m")(0.9652), 1e-9) {
+		t.Errorf("3 ft + 2 in should give 0.9652 m, got %v (%v)", v, err)
+	}
+}
+
+func TestRead(t *testing.T) {
+	const defs = `
+m       !
+twom    2 m \
+        # continued
+!endlocale
+!locale en_US
+!var
+!include missing.units
+!endvar
+foo(x)  x +
+bar(x   x
+`
+	d, err := unitdef.Read(strings.NewReader(defs), "test.units")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, diag := range d.Diagnostics {
+		got = append(got, diag.Error())
+	}
+	expected := []string{
+		`test.units:5: !endlocale without matching !locale`,
+		`test.units:7: !var requires a variable and values`,
+		`test.units:11: invalid function definition "bar(x   x"`,
+		`test.units:6: unterminated !locale section`,
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected diagnostics:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+
+	if v, err := d.Parse("twom"); err != nil || !v.Equal(si.Metre(2)) {
+		t.Errorf("twom should be 2 m, got %v (%v)", v, err)
+	}
+	if _, err := d.Parse("foo(2)"); err == nil {
+		t.Errorf("foo(2) should fail")
+	}
+
+	// Each section left open is reported where it was opened.
+	open, err := unitdef.Read(strings.NewReader("!utf8\n!locale en_US\n!var x 1\n"), "open.units")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = nil
+	for _, diag := range open.Diagnostics {
+		got = append(got, diag.Error())
+	}
+	expected = []string{
+		`open.units:1: unterminated !utf8 section`,
+		`open.units:2: unterminated !locale section`,
+		`open.units:3: unterminated !var section`,
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected diagnostics:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestBadIntervals(t *testing.T) {
+	const defs = `
+f(x) domain=] x
+g(x) range=) x
+h(x) domain=[0] x
+`
+	d, err := unitdef.Read(strings.NewReader(defs), "bad.units")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, diag := range d.Diagnostics {
+		got = append(got, diag.Error())
+	}
+	expected := []string{
+		`bad.units:2: f: invalid interval "]"`,
+		`bad.units:3: g: invalid interval ")"`,
+		`bad.units:4: h: invalid interval "[0]"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected diagnostics:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestFailedOnce(t *testing.T) {
+	d, err := unitdef.Read(strings.NewReader("foo !bogus\n"), "bogus.units")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := len(d.Diagnostics)
+	if n != 1 {
+		t.Fatalf("expected one diagnostic, got %v", d.Diagnostics)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := d.Parse("2 foo"); err == nil {
+			t.Errorf("foo should not be defined")
+		}
+	}
+	if len(d.Diagnostics) != n {
+		t.Errorf("looking up foo should not add diagnostics, got %v", d.Diagnostics)
+	}
+}
+
+func TestDefinitions(t *testing.T) {
+	t.Setenv("UNITSFILE", "testdata/sample.units")
+	d, err := unitdef.Definitions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Find("furlong") == nil {
+		t.Errorf("furlong should be defined")
+	}
+}
+
+func ExampleDefs_Must() {
+	defs, err := unitdef.Load("testdata/sample.units")
+	if err != nil {
+		panic(err)
+	}
+	mps := defs.Must("m/s")
+	dist := mps(5).Mul(si.Second(5))
+	fmt.Println(dist)
+	// Output: 25 m
+}
+
+func ExampleFunc() {
+	defs, err := unitdef.Load("testdata/sample.units")
+	if err != nil {
+		panic(err)
+	}
+	v, _ := defs.Func("tempF").Call(unit.Value{S: 212})
+	fmt.Println(unit.MustConvert(v, si.Kelvin))
+	c, _ := defs.Func("tempC").Inverse(v)
+	fmt.Printf("%.2f\n", c)
+	// Output:
+	// 373.15 K
+	// 100.00
+}
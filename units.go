@@ -8,9 +8,14 @@ import (
 // Units and its contents may be re-used internally and must not be directly
 // modified (including its numerator and denominator slices).  Units implements
 // Qualified, representing itself as a qualified value of 1.
+//
+// If K is non-nil, the units are marked as describing a quantity of that
+// kind (see Kind).  Mul, Div and Pow remove the mark, since the product of
+// two quantities is generally of a different kind.
 type Units struct {
 	N []Unit
 	D []Unit
+	K *Kind
 }
 
 /*
@@ -29,9 +34,14 @@ func (us Units) Empty() bool {
 
 // Recip returns the reciprocal of Units, swapping numerator and denominator.
 func (a Units) Recip() Units {
-	var r Units
-	r.N = append(r.N, a.D...)
-	r.D = append(r.D, a.N...)
+	// Since Units are immutable, the reciprocal can share a's lists,
+	// unless an affine unit needs to be replaced with its delta.
+	r := Units{N: a.D, D: a.N}
+	if r.hasAffine() {
+		r.N = append([]Unit(nil), r.N...)
+		r.D = append([]Unit(nil), r.D...)
+		r.relativize()
+	}
 	return r
 }
 
@@ -42,7 +52,9 @@ func (a Units) Value() float64 { return 1 }
 func (a Units) Units() Units { return a }
 
 // unitList represents a list of Units.  It implements sort.Interface to sort
-// by symbol name (sorting nil values last).
+// by symbol name (sorting nil values last).  Different units sharing a
+// symbol are ordered by their derivations, so that equal lists sort
+// identically.
 type unitList []Unit
 
 func (ul unitList) Len() int      { return len(ul) }
@@ -54,7 +66,21 @@ func (ul unitList) Less(a, b int) bool {
 	if ul[b] == nil {
 		return true
 	}
-	return ul[a].Symbol() < ul[b].Symbol()
+	if sa, sb := ul[a].Symbol(), ul[b].Symbol(); sa != sb {
+		return sa < sb
+	}
+	return derivLess(ul[a], ul[b])
+}
+
+// derivLess orders different units with the same symbol.
+func derivLess(a, b Unit) bool {
+	if a.Equal(b) {
+		return false
+	}
+	if fa, fb := unitDims(a).f, unitDims(b).f; fa != fb {
+		return fa < fb
+	}
+	return a.Deriv().String() < b.Deriv().String()
 }
 
 // Equal returns true if both unit lists contain equal units.  This method
@@ -116,13 +142,63 @@ func (ul unitList) String() string {
 */
 
 // Mul returns the multiplication of the two units, effectively creating
-// Units{N: a.N+b.N, D: a.D+b.D}.
+// Units{N: a.N+b.N, D: a.D+b.D}.  An absolute affine unit (see Affine) is
+// replaced by its delta unit.
 func (a Units) Mul(b Units) Units {
+	a, b = a.relative(), b.relative()
+	// Avoid allocating in the common cases.  Units are immutable, so
+	// the result can share the lists of a or b, provided they're already
+	// in the form cancel would leave them in.
+	switch {
+	case b.Empty() && a.canonical():
+		return Units{N: a.N, D: a.D}
+	case a.Empty() && b.canonical():
+		return Units{N: b.N, D: b.D}
+	case a.Equal(b) && a.canonical():
+		return Units{N: double(a.N), D: double(a.D)}
+	}
 	r := a.mul(b)
 	r.cancel()
 	return r
 }
 
+// canonical returns true if us is in the form cancel leaves units in,
+// ignoring its kind: each list sorted, with no nil units, no unit in both
+// lists, and no roots to combine or affine units to replace.
+func (us Units) canonical() bool {
+	if us.hasRoots() || (us.hasAffine() && us.absolute() == nil) {
+		return false
+	}
+	for _, l := range [][]Unit{us.N, us.D} {
+		for i, u := range l {
+			if u == nil || (i > 0 && unitList(l).Less(i, i-1)) {
+				return false
+			}
+		}
+	}
+	for _, n := range us.N {
+		for _, d := range us.D {
+			if n.Symbol() == d.Symbol() && n.Equal(d) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// double returns a sorted list with each unit of the sorted list l
+// repeated twice.
+func double(l []Unit) []Unit {
+	if len(l) == 0 {
+		return nil
+	}
+	r := make([]Unit, 0, 2*len(l))
+	for _, u := range l {
+		r = append(r, u, u)
+	}
+	return r
+}
+
 func (a Units) mul(b Units) Units {
 	var r Units
 	r.N = append(r.N, a.N...)
@@ -134,33 +210,51 @@ func (a Units) mul(b Units) Units {
 
 // Div returns the division of the two units, equivalent to a.Mul(b.Recip()).
 func (a Units) Div(b Units) Units {
+	switch {
+	case b.Empty():
+		return a.Mul(b)
+	case a.Equal(b):
+		return Units{}
+	}
 	return a.Mul(b.Recip())
 }
 
 // Pow returns a raised to the power of p, equivalent to multiplying a by
 // its original value p-1 times.  If p is 0, an empty Units will be returned.
 func (a Units) Pow(p int) Units {
-	// The type of p is int, not a float. This means it's not possible to
-	// reverse the effect of this function to move, say, from m^2/s^2 to m/s by
-	// passing in 0.5.
+	// The type of p is int, not a float.  Use PowRat to move, say, from
+	// m^2/s^2 to m/s.
 	if p < 0 {
 		return a.Recip().Pow(-p).Recip()
 	}
 	if p == 0 {
 		return Units{}
 	}
-	r := a
-	for p-1 > 0 {
-		r = r.mul(a)
-		p--
+	if a.Empty() {
+		return Units{}
+	}
+	if p == 1 && a.canonical() {
+		return Units{N: a.N, D: a.D}
+	}
+	if p != 1 {
+		a = a.relative()
+	}
+	r := Units{
+		N: make([]Unit, 0, len(a.N)*p),
+		D: make([]Unit, 0, len(a.D)*p),
+	}
+	for ; p > 0; p-- {
+		r.N = append(r.N, a.N...)
+		r.D = append(r.D, a.D...)
 	}
 	r.cancel()
 	return r
 }
 
-// Equal returns true if a and b contain the same units.
+// Equal returns true if a and b contain the same units, and are marked
+// with the same kind.
 func (a Units) Equal(b Units) bool {
-	return unitList(a.N).Equal(b.N) && unitList(a.D).Equal(b.D)
+	return a.K == b.K && unitList(a.N).Equal(b.N) && unitList(a.D).Equal(b.D)
 }
 
 // Equiv returns true if a and b reduce to the same primitive units.
@@ -168,14 +262,22 @@ func (a Units) Equiv(b Units) bool {
 	if a.Equal(b) {
 		return true
 	}
-	ar := a.Reduce()
-	br := b.Reduce()
-	return ar.U.Equal(br.U)
+	return a.dims().conforms(b.dims())
 }
 
 // Cancel identifies units in both the numerator and denominator and
 // removes them.  Units must be exact matches; no reduction is performed.
+// Affine units that end up combined with other units are replaced with
+// their delta units.
 func (a *Units) cancel() {
+	a.relativize()
+	if a.hasRoots() {
+		// Roots of the same unit need to be combined with each other (and
+		// with the unit itself), so take the slower path.
+		*a = fromTerms(a.terms())
+		return
+	}
+
 	var nr, dr int // read index to r.N and a.D
 	var nw, dw int // write index, always <= the read index
 
@@ -213,21 +315,32 @@ func (a *Units) cancel() {
 			dr++
 			dw++
 		} else {
-			// TODO: We need to scan forward for all equal symbols to find one
-			// for which Equal returns true, since we could have multiple units that
-			// have the same symbol but different definitions.
-			if !a.N[nr].Equal(a.D[dr]) {
-				if nr != nw {
+			// Units with the same symbol may still have different
+			// definitions (such as a foot and a survey foot), so each
+			// unit in the numerator's run of this symbol is cancelled
+			// with an equal unit from the denominator's run, if any.
+			sym := a.N[nr].Symbol()
+			ne, de := symbolRun(a.N, nr, sym), symbolRun(a.D, dr, sym)
+			for i := nr; i < ne; i++ {
+				for j := dr; j < de; j++ {
+					if a.D[j] != nil && a.N[i].Equal(a.D[j]) {
+						a.N[i], a.D[j] = nil, nil
+						break
+					}
+				}
+			}
+			for ; nr < ne; nr++ {
+				if a.N[nr] != nil {
 					a.N[nw] = a.N[nr]
+					nw++
 				}
-				nw++
-				if dr != dw {
+			}
+			for ; dr < de; dr++ {
+				if a.D[dr] != nil {
 					a.D[dw] = a.D[dr]
+					dw++
 				}
-				dw++
 			}
-			nr++
-			dr++
 		}
 	}
 	// anything left at the end of a.N or a.D should be kept
@@ -249,57 +362,24 @@ func (a *Units) cancel() {
 	a.D = a.D[:dw]
 }
 
-func reduceLine(left []Unit, start int) (mult float64, res, right []Unit) {
-	defer tracein("reduceLine(%v, %d)", left, start)()
-	mult = 1
-	for i := start; i < len(left); i++ {
-		tracemsg("item %d=%v primitive? %v", i, left[i], IsPrimitive(left[i]))
-		if !IsPrimitive(left[i]) {
-			tracemsg("- %v.Deriv() = %v", left[i], left[i].Deriv())
-			val := left[i].Deriv().Reduce()
-			mult *= val.Value()
-			us := val.Units()
-			left = append(left, us.N...)
-			right = append(right, us.D...)
-			tracemsg("- replacing %v with %v", left[i], us)
-			left[i] = nil
-		}
+// symbolRun returns the end of the run of units with symbol sym in the
+// sorted list l, starting at i.
+func symbolRun(l []Unit, i int, sym string) int {
+	for i < len(l) && l[i] != nil && l[i].Symbol() == sym {
+		i++
 	}
-	tracemsg("= mult=%v left=%v right=%v", mult, left, right)
-	return mult, left, right
+	return i
 }
 
 // Reduce reduces us to primitive units.  The return type is a Value since
-// the act of reducing may introduce a multiplier.
+// the act of reducing may introduce a multiplier.  The reduction of each
+// named unit is computed once and cached, and the result for us combines
+// those of its parts.  If the multiplier is known exactly (see Value.R),
+// the result carries it as its exact scalar.
 func (us Units) Reduce() (r Value) {
 	defer tracein("%q.Reduce()", us)()
-	var u Units
-	u.N = append([]Unit{}, us.N...)
-	u.D = append([]Unit{}, us.D...)
-
-	var mult float64 = 1
-	var lastn, lastd int
-	var recip []Unit
-	var n float64
-	for {
-		tracemsg("starting pass: %q", u)
-		n, u.N, recip = reduceLine(u.N, lastn)
-		mult *= n
-		lastn = len(u.N)
-		u.D = append(u.D, recip...)
-
-		n, u.D, recip = reduceLine(u.D, lastd)
-		mult /= n
-		lastd = len(u.D)
-		if recip == nil {
-			break
-		}
-		u.N = append(u.N, recip...)
-	}
-	tracemsg("done: %q", u)
-	u.cancel()
-	tracemsg("= %q", Value{S: mult, U: u})
-	return Value{S: mult, U: u}
+	d := us.dims()
+	return Value{S: d.f, R: us.exactFactor(), U: d.units()}
 }
 
 // Make creates a new qualified value.
@@ -79,6 +79,59 @@ func TestRecip(t *testing.T) {
 	}
 }
 
+func TestFastPaths(t *testing.T) {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+	dc := unit.Derive("Δ°C", unit.Primitive("K"))
+	c := unit.Affine("°C", dc, 273.15)
+	mps := m.Div(s).Units()
+	// Units built directly need not be sorted or cancelled, and may carry
+	// a kind, which the fast paths must not preserve.
+	unsorted := unit.Units{N: []unit.Unit{s.Unit(), m.Unit()}}
+	uncancelled := unit.Units{N: []unit.Unit{s.Unit(), m.Unit()}, D: []unit.Unit{m.Unit()}}
+	freq := unit.NewKind("frequency", nil)
+	perSecond := unit.Units{D: []unit.Unit{s.Unit()}, K: freq}
+
+	for _, x := range []struct {
+		desc        string
+		r, expected unit.Units
+	}{
+		{"unitless", mps.Mul(unit.Units{}), mps},
+		{"unitless left", unit.Units{}.Mul(mps), mps},
+		{"same", mps.Mul(mps), m.Pow(2).Div(s.Pow(2)).Units()},
+		{"div unitless", mps.Div(unit.Units{}), mps},
+		{"div same", mps.Div(mps), unit.Units{}},
+		{"pow 1", mps.Pow(1), mps},
+		{"affine same", c.Units().Mul(c.Units()), dc.Pow(2).Units()},
+		{"affine recip", c.Units().Recip(), unit.Scalar(1).Div(dc).Units()},
+		{"unsorted", unsorted.Mul(unit.Units{}), m.Mul(s).Units()},
+		{"unsorted left", unit.Units{}.Mul(unsorted), m.Mul(s).Units()},
+		{"uncancelled", uncancelled.Mul(unit.Units{}), s.Units()},
+		{"uncancelled left", unit.Units{}.Mul(uncancelled), s.Units()},
+		{"uncancelled same", uncancelled.Mul(uncancelled), s.Pow(2).Units()},
+		{"div uncancelled", uncancelled.Div(unit.Units{}), s.Units()},
+		{"pow 1 uncancelled", uncancelled.Pow(1), s.Units()},
+		{"kind", perSecond.Mul(unit.Units{}), unit.Scalar(1).Div(s).Units()},
+		{"kind left", unit.Units{}.Mul(perSecond), unit.Scalar(1).Div(s).Units()},
+		{"div kind", perSecond.Div(unit.Units{}), unit.Scalar(1).Div(s).Units()},
+		{"pow 1 kind", perSecond.Pow(1), unit.Scalar(1).Div(s).Units()},
+	} {
+		if !x.r.Equal(x.expected) {
+			t.Errorf("%s: expected %q, got %q", x.desc, x.expected, x.r)
+		}
+	}
+
+	if n := testing.AllocsPerRun(100, func() { mps.Mul(unit.Units{}) }); n != 0 {
+		t.Errorf("multiplying by unitless should not allocate, got %v allocs", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { mps.Div(mps) }); n != 0 {
+		t.Errorf("dividing by identical units should not allocate, got %v allocs", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { mps.Recip() }); n != 0 {
+		t.Errorf("reciprocal should not allocate, got %v allocs", n)
+	}
+}
+
 func TestEqual(t *testing.T) {
 	a := unit.Primitive("a")
 	b := unit.Primitive("b")
@@ -111,6 +164,30 @@ func TestEquiv(t *testing.T) {
 	}
 }
 
+func TestEquivDerived(t *testing.T) {
+	a := unit.Primitive("a")
+	b := unit.Primitive("b")
+	c := unit.Derive("c", a.Div(b)(3))
+	d := unit.Derive("d", c.Pow(2).Mul(b))
+	e := unit.Derive("e", a.PowRat(1, 2))
+
+	for _, x := range []struct {
+		x, y  unit.Units
+		equiv bool
+	}{
+		{d.Units(), a.Pow(2).Div(b).Units(), true},
+		{d.Units(), c.Mul(a).Units(), true},
+		{d.Units(), c.Units(), false},
+		{e.Pow(2).Units(), a.Units(), true},
+		{e.Units(), a.Units(), false},
+		{e.Mul(c).Units(), a.PowRat(3, 2).Div(b).Units(), true},
+	} {
+		if x.x.Equiv(x.y) != x.equiv {
+			t.Errorf("%q.Equiv(%q) should be %v", x.x, x.y, x.equiv)
+		}
+	}
+}
+
 /*
 func TestCancel(t *testing.T) {
 	a := unit.Primitive("a").Unit()
@@ -148,6 +225,43 @@ func TestReduce(t *testing.T) {
 	}
 }
 
+// foreignUnit is a Unit implemented outside the unit package.  It is
+// comparable, but its tag may hold a value that is not.
+type foreignUnit struct {
+	symbol string
+	tag    interface{}
+}
+
+var foreignBase = unit.Primitive("fb")
+
+func (u foreignUnit) Symbol() string            { return u.symbol }
+func (u foreignUnit) Deriv() unit.Value         { return foreignBase(2) }
+func (u foreignUnit) Units() unit.Units         { return unit.Units{N: []unit.Unit{u}} }
+func (u foreignUnit) Value() float64            { return 1 }
+func (u foreignUnit) Make(v float64) unit.Value { return u.Units().Make(v) }
+func (u foreignUnit) Equal(o unit.Unit) bool {
+	return o != nil && u.Symbol() == o.Symbol() && u.Deriv().Equal(o.Deriv())
+}
+
+func TestReduceForeign(t *testing.T) {
+	for _, u := range []foreignUnit{
+		{"f1", nil},
+		{"f2", []int{1}}, // not hashable
+	} {
+		if r := u.Make(3).Reduce(); !r.Equal(foreignBase(6)) {
+			t.Errorf("3 %s should reduce to 6 fb, got %v", u.symbol, r)
+		}
+	}
+	// Many distinct foreign units can be reduced, without being cached
+	// without bound.
+	for i := 0; i < 5000; i++ {
+		u := foreignUnit{fmt.Sprintf("f%d", i), i}
+		if r := u.Make(1).Reduce(); !r.Equal(foreignBase(2)) {
+			t.Fatalf("1 %s should reduce to 2 fb, got %v", u.symbol, r)
+		}
+	}
+}
+
 func TestMakeWithMultiply(t *testing.T) {
 	a := unit.Primitive("a")
 	ka := a.Mul(unit.Scalar(1000))
@@ -218,3 +332,31 @@ func ExampleUnits() {
 	// u.Equal(r)? false
 	// u.Equiv(r)? true
 }
+
+func TestCancelSameSymbol(t *testing.T) {
+	m := unit.Primitive("m")
+	s := unit.Primitive("s")
+	min := unit.Derive("m", s(60)) // a minute, with a colliding symbol
+
+	for _, x := range []struct {
+		desc        string
+		r, expected unit.Units
+	}{
+		{"cancel metre", m.Mul(min).Div(m).Units(), min.Units()},
+		{"cancel minute", m.Mul(min).Div(min).Units(), m.Units()},
+		{"cancel both", m.Mul(min).Div(min.Mul(m)).Units(), unit.Units{}},
+		{"keep both", m.Div(min).Units(), unit.Units{N: []unit.Unit{m.Unit()}, D: []unit.Unit{min.Unit()}}},
+		{"order", min.Mul(m).Units(), m.Mul(min).Units()},
+	} {
+		if !x.r.Equal(x.expected) {
+			t.Errorf("%s: expected %q, got %q", x.desc, x.expected, x.r)
+		}
+	}
+
+	if got, want := m.Div(min).Units().String(), "m/m[60 s]"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got, want := m.Pow(2).Units().String(), "m^2"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
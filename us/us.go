@@ -2,6 +2,7 @@ package us
 
 import (
 	"math"
+	"math/big"
 
 	"github.com/dnesting/unit"
 	"github.com/dnesting/unit/si"
@@ -14,14 +15,14 @@ var Deg = unit.NewRegistry("degree", Registry)
 
 var (
 	Inch  = Registry.Derive("in", si.Meter(0.0254), "\"", "″")
-	Pica  = Registry.Derive("P̸", Inch(1.0/6))
-	Point = Registry.Derive("p", Inch(1.0/72))
+	Pica  = Registry.Derive("P̸", Inch.MakeRat(big.NewRat(1, 6)))
+	Point = Registry.Derive("p", Inch.MakeRat(big.NewRat(1, 72)))
 	Foot  = Registry.Derive("ft", Inch(12), "'", "′")
 	Yard  = Registry.Derive("yd", Foot(3))
 	Mile  = Registry.Derive("mi", Foot(5280))
 
-	SurveyFoot = Survey.Derive("ft", si.Meter(1200.0/3937))
-	SurveyMile = Survey.Derive("mi", si.Meter(6336000.0/3937))
+	SurveyFoot = Survey.Derive("ft", si.Meter.MakeRat(big.NewRat(1200, 3937)))
+	SurveyMile = Survey.Derive("mi", si.Meter.MakeRat(big.NewRat(6336000, 3937)))
 
 	Fathom       = Registry.Derive("ftm", Yard(2))
 	NauticalMile = Registry.Derive("NM", si.Meter(1852), "nmi")
@@ -42,23 +43,70 @@ var (
 	Pound = Registry.Derive("lb", Ounce(16))
 	Ton   = Registry.Derive("ton", Pound(2000))
 
-	DegFahrenheit = Registry.Derive("°F", si.DegCelsius.Mul(unit.Scalar(float64(5)/9)), "℉", "degF")
-	Calorie       = Registry.Derive("cal", si.Joule(4.184))
-	KiloCalorie   = Registry.Derive("kcal", si.Joule(4184), "Cal")
-	PoundForce    = Registry.Derive("lbf", Pound.Mul(si.Meter.Div(si.Second.Pow(2)))(9.80665))
+	// DeltaFahrenheit is a difference of one degree on the Fahrenheit
+	// scale, and DegFahrenheit an absolute reading on it.
+	DeltaFahrenheit = Registry.Derive("Δ°F", si.Kelvin.MakeRat(big.NewRat(5, 9)), "ΔdegF")
+	DegFahrenheit   = Registry.Affine("°F", DeltaFahrenheit, 459.67, "℉", "degF")
+
+	Calorie     = Registry.Derive("cal", si.Joule(4.184))
+	KiloCalorie = Registry.Derive("kcal", si.Joule(4184), "Cal")
+	PoundForce  = Registry.Derive("lbf", Pound.Mul(si.Meter.Div(si.Second.Pow(2)))(9.80665))
 
 	Second = si.Second
-	Minute = Registry.Derive("m", Second(60))
+	Minute = Registry.Derive("min", Second(60))
 	Hour   = Registry.Derive("h", Minute(60))
 	Day    = Registry.Derive("d", Hour(24))
 
-	Degree    = Registry.Derive("°", si.Radian(1).DivN(2*math.Pi).MulN(360), "deg")
+	Degree    = Registry.Derive("°", si.Radian(math.Pi/180), "deg")
 	DegMinute = Deg.Derive("'", Degree(1.0/60))
 	DegSecond = Deg.Derive("\"", DegMinute(1.0/60))
 )
 
+// Long names and descriptions of the units above, for unit.WithLongNames
+// and for parsing names such as "5 feet".
 func init() {
-	unit.DefaultFormatter.Config(unit.WithNoGapFor(Degree, DegMinute, DegSecond))
+	for _, d := range []struct {
+		r    *unit.Registry
+		m    unit.Maker
+		info unit.Info
+	}{
+		{Registry, Inch, unit.Info{Name: "inch", Plural: "inches", Description: "length; exactly 25.4 mm"}},
+		{Registry, Pica, unit.Info{Name: "pica", Description: "typographic length; 1/6 inch"}},
+		{Registry, Point, unit.Info{Name: "point", Description: "typographic length; 1/72 inch"}},
+		{Registry, Foot, unit.Info{Name: "foot", Plural: "feet", Description: "length; 12 inches"}},
+		{Registry, Yard, unit.Info{Name: "yard", Description: "length; 3 feet"}},
+		{Registry, Mile, unit.Info{Name: "mile", Description: "length; 5280 feet"}},
+		{Survey, SurveyFoot, unit.Info{Name: "survey foot", Plural: "survey feet", Description: "length used in US land surveys; 1200/3937 m"}},
+		{Survey, SurveyMile, unit.Info{Name: "survey mile", Description: "length used in US land surveys; 5280 survey feet"}},
+		{Registry, Fathom, unit.Info{Name: "fathom", Description: "depth of water; 2 yards"}},
+		{Registry, NauticalMile, unit.Info{Name: "nautical mile", Description: "length used in navigation; exactly 1852 m"}},
+		{Registry, Acre, unit.Info{Name: "acre", Description: "area of land; 43560 square survey feet"}},
+		{Registry, Teaspoon, unit.Info{Name: "teaspoon", Description: "volume used in cooking"}},
+		{Registry, Tablespoon, unit.Info{Name: "tablespoon", Description: "volume used in cooking; 3 teaspoons"}},
+		{Fluid, FluidOunce, unit.Info{Name: "fluid ounce", Description: "volume; 2 tablespoons"}},
+		{Registry, Shot, unit.Info{Name: "jigger", Description: "volume used for spirits; 3 tablespoons"}},
+		{Registry, Cup, unit.Info{Name: "cup", Description: "volume; 8 fluid ounces"}},
+		{Registry, Pint, unit.Info{Name: "pint", Description: "volume; 2 cups"}},
+		{Registry, Quart, unit.Info{Name: "quart", Description: "volume; 2 pints"}},
+		{Registry, Gallon, unit.Info{Name: "gallon", Description: "volume; 4 quarts"}},
+		{Registry, Dram, unit.Info{Name: "dram", Description: "mass; 1/16 ounce"}},
+		{Registry, Ounce, unit.Info{Name: "ounce", Description: "mass; 1/16 pound"}},
+		{Registry, Pound, unit.Info{Name: "pound", Description: "mass; exactly 0.45359237 kg"}},
+		{Registry, Ton, unit.Info{Name: "ton", Description: "mass; 2000 pounds"}},
+		{Registry, DegFahrenheit, unit.Info{Name: "degree Fahrenheit", Plural: "degrees Fahrenheit", Description: "temperature on the Fahrenheit scale"}},
+		{Registry, Calorie, unit.Info{Name: "calorie", Description: "energy; the thermochemical calorie"}},
+		{Registry, KiloCalorie, unit.Info{Name: "kilocalorie", Description: "energy; the food Calorie"}},
+		{Registry, PoundForce, unit.Info{Name: "pound-force", Plural: "pounds-force", Description: "force exerted by a pound under standard gravity"}},
+		{Registry, Minute, unit.Info{Name: "minute", Description: "time; 60 seconds", NoPrefixes: true}},
+		{Registry, Hour, unit.Info{Name: "hour", Description: "time; 60 minutes", NoPrefixes: true}},
+		{Registry, Day, unit.Info{Name: "day", Description: "time; 24 hours", NoPrefixes: true}},
+		{Registry, Degree, unit.Info{Name: "degree", Description: "plane angle; π/180 rad", NoPrefixes: true, NoSpace: true}},
+		{Deg, DegMinute, unit.Info{Name: "arcminute", Description: "plane angle; 1/60 degree", NoPrefixes: true, NoSpace: true}},
+		{Deg, DegSecond, unit.Info{Name: "arcsecond", Description: "plane angle; 1/60 arcminute", NoPrefixes: true, NoSpace: true}},
+	} {
+		d.info.System = "US customary"
+		d.r.Describe(d.m, d.info)
+	}
 }
 
 func ToDMS(deg unit.Value) (d, m, s unit.Value, ok bool) {
@@ -81,22 +129,24 @@ func ToDMS(deg unit.Value) (d, m, s unit.Value, ok bool) {
 	return
 }
 
-func ToCelsius(degF unit.Value) (c unit.Value, ok bool) {
-	fvalue, remain := degF.Convert(DegFahrenheit)
+// ToCelsius converts a DegFahrenheit value to si.DegCelsius.  Returns
+// false if degF cannot be converted to DegFahrenheit.  This is equivalent
+// to converting degF to si.DegCelsius.
+func ToCelsius(degF unit.Value) (unit.Value, bool) {
+	c, remain := degF.Convert(si.DegCelsius)
 	if !remain.Empty() {
-		return
+		return unit.Value{}, false
 	}
-	fvalue.S -= 32
-	fvalue, _ = fvalue.Convert(si.DegCelsius)
-	return fvalue, true
+	return c, true
 }
 
-func ToFahrenheit(degC unit.Value) (f unit.Value, ok bool) {
-	cvalue, remain := degC.Convert(si.DegCelsius)
+// ToFahrenheit converts a si.DegCelsius value to DegFahrenheit.  Returns
+// false if degC cannot be converted to si.DegCelsius.  This is equivalent
+// to converting degC to DegFahrenheit.
+func ToFahrenheit(degC unit.Value) (unit.Value, bool) {
+	f, remain := degC.Convert(DegFahrenheit)
 	if !remain.Empty() {
-		return
+		return unit.Value{}, false
 	}
-	cvalue, _ = cvalue.Convert(DegFahrenheit)
-	cvalue.S += 32
-	return cvalue, true
+	return f, true
 }
@@ -1,8 +1,11 @@
 package us_test
 
 import (
+	"math"
+	"math/big"
 	"testing"
 
+	"github.com/dnesting/unit"
 	"github.com/dnesting/unit/si"
 	"github.com/dnesting/unit/us"
 )
@@ -56,3 +59,116 @@ func TestDMS(t *testing.T) {
 			orig, ed, em, es, d, m, s, ok)
 	}
 }
+
+func TestConvertFahrenheit(t *testing.T) {
+	c := si.DegCelsius(20)
+	e := us.DegFahrenheit(68)
+	r, remain := c.Convert(us.DegFahrenheit)
+	if !remain.Empty() || !e.Approx(r, 0.0000000001) {
+		t.Errorf("%q.Convert(°F) should give %q, got %q (remain=%q)", c, e, r, remain)
+	}
+
+	d, ok := us.DegFahrenheit(212).Sub(us.DegFahrenheit(32))
+	if e := si.DeltaCelsius(100); !ok || !e.Approx(d, 0.0000000001) {
+		t.Errorf("212 °F - 32 °F should be %q, got %q (ok=%v)", e, d, ok)
+	}
+}
+
+func TestExactRoundTrip(t *testing.T) {
+	a := us.Mile(1).Exact()
+	b := a
+	for _, m := range []unit.Maker{us.Inch, si.Meter, us.Foot, us.Yard, si.Kilo(si.Meter), us.Mile} {
+		b, _ = b.Convert(m)
+	}
+	if b.R == nil || b.R.Cmp(a.R) != 0 {
+		t.Errorf("%q should round trip exactly, got %v (%q)", a, b.R, b)
+	}
+
+	tsp, _ := us.Gallon(1).Exact().Convert(us.Teaspoon)
+	if tsp.S != 768 {
+		t.Errorf("1 gal should be exactly 768 tsp, got %v", tsp.R)
+	}
+
+	// The metre and survey foot both reduce to the same primitive units,
+	// by exact factors whose ratio is exactly 1200/3937.
+	sft, m := us.SurveyFoot.Units().Reduce(), si.Meter.Units().Reduce()
+	if sft.R == nil || m.R == nil || new(big.Rat).Quo(sft.R, m.R).Cmp(big.NewRat(1200, 3937)) != 0 {
+		t.Errorf("ft(survey) should reduce to exactly 1200/3937 of a metre's reduction, got %v and %v", sft.R, m.R)
+	}
+	if r := us.SurveyFoot(3).Exact().Reduce(); r.R == nil || r.R.Cmp(new(big.Rat).Mul(sft.R, big.NewRat(3, 1))) != 0 {
+		t.Errorf("3 ft(survey) should reduce exactly to 3 times %v, got %v (%q)", sft.R, r.R, r)
+	}
+	if r := us.SurveyFoot(3).Reduce(); r.R != nil || math.Abs(r.S-3*sft.S) > 1e-9 {
+		t.Errorf("3 ft(survey) without an exact scalar should reduce to %v, got %v (R=%v)", 3*sft.S, r.S, r.R)
+	}
+}
+
+func TestDegree(t *testing.T) {
+	r, remain := us.Degree(180).Convert(si.Radian)
+	if !remain.Empty() || math.Abs(r.S-math.Pi) > 1e-12 {
+		t.Errorf("180 ° should convert to π rad, got %v (remain %q)", r, remain)
+	}
+	d, remain := si.Radian(math.Pi / 2).Convert(us.Degree)
+	if !remain.Empty() || math.Abs(d.S-90) > 1e-12 {
+		t.Errorf("π/2 rad should convert to 90 °, got %v (remain %q)", d, remain)
+	}
+	if _, remain := us.Degree(90).Convert(unit.Unity); remain.Empty() {
+		t.Errorf("° should not convert to a bare scalar")
+	}
+}
+
+func TestSymbolCollisions(t *testing.T) {
+	if got := us.Minute.Units().String(); got != "min" {
+		t.Errorf("minutes should be formatted as min, got %q", got)
+	}
+	survey := unit.NewFormatter(unit.WithRegistry(us.Survey))
+	if got, want := survey.FormatUnits(us.Foot.Mul(us.SurveyFoot).Units()), "us:ft survey:ft"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	fluid := unit.NewFormatter(unit.WithRegistry(us.Fluid))
+	if got, want := fluid.FormatUnits(us.Ounce.Div(us.FluidOunce).Units()), "us:oz/fluid:oz"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	r := us.Foot.Mul(us.SurveyFoot).Div(us.Foot).Units()
+	if !r.Equal(us.SurveyFoot.Units()) {
+		t.Errorf("ft survey:ft/ft should cancel to survey:ft, got %q", r)
+	}
+	if v, err := unit.Parse("3 survey:ft", us.Survey, true); err != nil || !v.U.Equal(us.SurveyFoot.Units()) {
+		t.Errorf("3 survey:ft should parse as survey feet, got %v (err=%v)", v, err)
+	}
+	if v, err := unit.Parse("3 survey:ft", us.Fluid, true); err == nil {
+		t.Errorf("survey:ft should not be found from the fluid registry, got %v", v)
+	}
+}
+
+func TestLongNames(t *testing.T) {
+	if v, err := unit.Parse("6 feet", us.Registry, true); err != nil || !v.Equal(us.Foot(6)) {
+		t.Errorf("6 feet should parse as 6 ft, got %v (err=%v)", v, err)
+	}
+	if v, err := unit.Parse("3 survey feet", us.Survey, true); err == nil {
+		t.Errorf("names with spaces should not parse, got %v", v)
+	}
+	if v, err := unit.Parse("3 nautical miles", us.Registry, true); err == nil {
+		t.Errorf("names with spaces should not parse, got %v", v)
+	}
+	f := unit.NewFormatter(unit.WithLongNames(us.Survey))
+	for _, x := range []struct {
+		v        unit.Value
+		expected string
+	}{
+		{us.Foot(1), "1 foot"},
+		{us.Foot(6), "6 feet"},
+		{us.Mile.Div(us.Hour)(60), "60 miles per hour"},
+		{us.SurveyFoot(2), "2 survey feet"},
+	} {
+		if got := f.Format(x.v); got != x.expected {
+			t.Errorf("expected %q, got %q", x.expected, got)
+		}
+	}
+	if got := unit.NewFormatter(unit.WithRegistry(us.Registry)).Format(us.Degree(90)); got != "90°" {
+		t.Errorf("degrees should be formatted without a gap, got %q", got)
+	}
+	if us.Registry.Find("kmin") != nil {
+		t.Errorf("minutes should not take prefixes")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"strings"
 )
 
@@ -17,16 +18,21 @@ type Qualified interface {
 	Units() Units
 }
 
-// Value is a concrete value with associated units.
+// Value is a concrete value with associated units.  If R is non-nil, it
+// holds the scalar exactly, and S holds its nearest float64 approximation.
+// Values with an exact scalar keep it through arithmetic and conversions
+// for as long as the conversion factors involved are exact.  R must not
+// be modified.
 type Value struct {
 	S float64
 	U Units
+	R *big.Rat
 }
 
 // Compare matches units of a and b, and calls cmpFn to compare the
-// scalar values.  Returns the result of cmpFn, or an Incomparable error
-// if a and b do not reduce to the same primitive units.  If cmpFn is nil,
-// values will be compared for equality.
+// scalar values.  Returns the result of cmpFn, or a *ConversionError
+// (wrapping Incomparable) if a and b do not reduce to the same primitive
+// units.  If cmpFn is nil, values will be compared for equality.
 func (a Value) Compare(b Qualified, cmpFn func(a, b float64) bool) (bool, error) {
 	if a.Units().Equal(b.Units()) {
 		if cmpFn == nil {
@@ -34,16 +40,54 @@ func (a Value) Compare(b Qualified, cmpFn func(a, b float64) bool) (bool, error)
 		}
 		return cmpFn(a.Value(), b.Value()), nil
 	}
-	ab, remain := a.Convert(b.Units().Make)
-	if !remain.Empty() {
-		return false, fmt.Errorf("%w: %q != %q (diff=%q)", Incomparable, a.Units(), b.Units(), remain)
+	if a.U.logarithmic() != nil || b.Units().logarithmic() != nil {
+		bv, remain := FromQualified(b).Convert(a.U.Make)
+		if !remain.Empty() {
+			return false, newConversionError(a.U, b.Units())
+		}
+		if cmpFn == nil {
+			return a.S == bv.S, nil
+		}
+		return cmpFn(a.S, bv.S), nil
+	}
+	da, db := a.U.dims(), b.Units().dims()
+	if !da.conforms(db) || !kindsAgree(a.U, b.Units()) {
+		return false, newConversionError(a.U, b.Units())
+	}
+	from, to := offsets(a.U, b.Units())
+	if cmpFn == nil {
+		return (a.S+from)*da.f/db.f-to == b.Value(), nil
 	}
-	return cmpFn(ab.Value(), b.Value()), nil
+	return cmpFn((a.S+from)*da.f/db.f-to, b.Value()), nil
+}
+
+// Cmp compares a and b, converting a into b's units, and returns -1, 0 or
+// +1 if a is less than, equal to or greater than b.  Returns an
+// Incomparable error if a and b do not reduce to the same primitive
+// units.
+func (a Value) Cmp(b Qualified) (int, error) {
+	var c int
+	_, err := a.Compare(b, func(a, b float64) bool {
+		switch {
+		case a < b:
+			c = -1
+		case a > b:
+			c = 1
+		}
+		return c == 0
+	})
+	return c, err
 }
 
 // Equal returns true if the units for a and b are equivalent, and the
-// scalar values are equal.
+// scalar values are equal.  If either a or b has an exact scalar, and the
+// conversion between them is exact, they are compared exactly.
 func (a Value) Equal(b Qualified) bool {
+	if a.R != nil || ratOf(b) != nil {
+		if r, remain := a.Exact().Convert(b.Units().Make); remain.Empty() && r.R != nil {
+			return r.R.Cmp(FromQualified(b).exact()) == 0
+		}
+	}
 	ok, _ := a.Compare(b, func(a, b float64) bool { return a == b })
 	return ok
 }
@@ -55,14 +99,23 @@ func (a Value) Approx(b Qualified, within float64) bool {
 	return ok
 }
 
-/*
+// ApproxRel returns true if the units for a and b are equivalent, and the
+// scalar values differ by no more than rel times the larger of their
+// magnitudes, so that ApproxRel(b, 1e-9) ignores floating point error
+// regardless of scale.
+func (a Value) ApproxRel(b Qualified, rel float64) bool {
+	ok, _ := a.Compare(b, func(a, b float64) bool {
+		return math.Abs(a-b) <= rel*math.Max(math.Abs(a), math.Abs(b))
+	})
+	return ok
+}
+
 // Less returns true if the units for a and b are equivalent, and the
 // scalar value of a is less than b.  Returns an Incomparable error if
 // the units are not conformable.
 func (a Value) Less(b Qualified) (bool, error) {
 	return a.Compare(b, func(a, b float64) bool { return a < b })
 }
-*/
 
 // Value returns the scalar (float64) component of v.
 func (v Value) Value() float64 { return v.S }
@@ -75,6 +128,9 @@ func (v Value) Units() Units { return v.U }
 func (a Value) MulN(b float64) (r Value) {
 	r.S = a.S * b
 	r.U = a.U
+	if a.R != nil {
+		r = r.withRat(mulRat(a.R, decimal(b)))
+	}
 	return
 }
 
@@ -82,6 +138,9 @@ func (a Value) MulN(b float64) (r Value) {
 func (a Value) Mul(b Qualified) (r Value) {
 	r.S = a.S * b.Value()
 	r.U = a.U.Mul(b.Units())
+	if a.R != nil || ratOf(b) != nil {
+		r = r.withRat(mulRat(a.exact(), FromQualified(b).exact()))
+	}
 	return
 }
 
@@ -90,6 +149,9 @@ func (a Value) Mul(b Qualified) (r Value) {
 func (a Value) DivN(b float64) (r Value) {
 	r.S = a.S / b
 	r.U = a.U
+	if a.R != nil {
+		r = r.withRat(quoRat(a.R, decimal(b)))
+	}
 	return
 }
 
@@ -97,6 +159,9 @@ func (a Value) DivN(b float64) (r Value) {
 func (a Value) Div(b Qualified) (r Value) {
 	r.S = a.S / b.Value()
 	r.U = a.U.Div(b.Units())
+	if a.R != nil || ratOf(b) != nil {
+		r = r.withRat(quoRat(a.exact(), FromQualified(b).exact()))
+	}
 	return
 }
 
@@ -104,6 +169,9 @@ func (a Value) Div(b Qualified) (r Value) {
 func (a Value) AddN(b float64) (r Value) {
 	r.S = a.S + b
 	r.U = a.U
+	if a.R != nil {
+		r = r.withRat(addRat(a.R, decimal(b)))
+	}
 	return
 }
 
@@ -119,13 +187,37 @@ func (a Value) conform(b Value) (r Value, ok bool) {
 }
 
 // Add adds a and b, returning the result.  If the units are not
-// equivalent, r will be empty and ok will be false.
+// equivalent, r will be empty and ok will be false.  If either a or b is
+// an absolute reading on an affine scale (see Affine), the other is
+// treated as a difference and the result is an absolute reading.  Adding
+// two absolute readings fails.
+//
+// If a's units are logarithmic (see Logarithmic), adding a gain shifts a
+// by the gain, and adding another level sums the two as powers.
 func (a Value) Add(b Value) (r Value, ok bool) {
-	if b, ok = a.conform(b); !ok {
+	if la := a.U.logarithmic(); la != nil {
+		return a.addLog(la, b, 1)
+	}
+	conformTo := a.U
+	switch aa, ba := a.U.absolute(), b.U.absolute(); {
+	case aa != nil && ba != nil:
+		return
+	case ba != nil:
+		return b.Add(a)
+	case aa != nil:
+		conformTo = aa.delta.Units()
+	}
+	if a.R != nil || b.R != nil {
+		a, b = a.Exact(), b.Exact()
+	}
+	if b, ok = (Value{U: conformTo}).conform(b); !ok {
 		return
 	}
 	r.S = a.S + b.S
 	r.U = a.U
+	if b.R != nil {
+		r = r.withRat(addRat(a.R, b.R))
+	}
 	ok = true
 	return
 }
@@ -134,17 +226,47 @@ func (a Value) Add(b Value) (r Value, ok bool) {
 func (a Value) SubN(b float64) (r Value) {
 	r.S = a.S - b
 	r.U = a.U
+	if a.R != nil {
+		r = r.withRat(subRat(a.R, decimal(b)))
+	}
 	return
 }
 
 // Sub subtracts b from a, returning the result.  If the units are not
-// equivalent, r will be empty and ok will be false.
+// equivalent, r will be empty and ok will be false.  If a and b are both
+// absolute readings on an affine scale (see Affine), the result is a
+// difference in a's delta units.  If only a is absolute, b is treated as a
+// difference and the result is absolute.  Subtracting an absolute reading
+// from anything else fails.
+//
+// If a's units are logarithmic (see Logarithmic), subtracting a gain
+// shifts a down by the gain, and subtracting another level takes the
+// difference of the two as powers.  Subtracting a level from one no
+// greater than it fails, since the difference has no level.
 func (a Value) Sub(b Value) (r Value, ok bool) {
-	if b, ok = a.conform(b); !ok {
+	if la := a.U.logarithmic(); la != nil {
+		return a.addLog(la, b, -1)
+	}
+	conformTo, resultU := a.U, a.U
+	switch aa, ba := a.U.absolute(), b.U.absolute(); {
+	case aa == nil && ba != nil:
+		return
+	case aa != nil && ba == nil:
+		conformTo = aa.delta.Units()
+	case aa != nil:
+		resultU = aa.delta.Units()
+	}
+	if a.R != nil || b.R != nil {
+		a, b = a.Exact(), b.Exact()
+	}
+	if b, ok = (Value{U: conformTo}).conform(b); !ok {
 		return
 	}
 	r.S = a.S - b.S
-	r.U = a.U
+	r.U = resultU
+	if b.R != nil {
+		r = r.withRat(subRat(a.R, b.R))
+	}
 	ok = true
 	return
 }
@@ -153,6 +275,9 @@ func (a Value) Sub(b Value) (r Value, ok bool) {
 func (a Value) Pow(n int) (r Value) {
 	r.S = math.Pow(a.S, float64(n))
 	r.U = a.U.Pow(n)
+	if a.R != nil {
+		r = r.withRat(ratPow(a.R, n))
+	}
 	return
 }
 
@@ -161,23 +286,60 @@ func (a Value) Pow(n int) (r Value) {
 // a and wanted.  For instance, "5 m/s".Convert("m") will return ("5 m",
 // "/s"), and "5 m".Convert("s") will return ("5 s", "m/s").  It should
 // always be true that remain.Mul(result) will return the original value.
+// Any remainder is expressed in primitive units.
+//
+// If a or wanted is an absolute reading on an affine scale (see Affine),
+// and both are single units, the scales' offsets are applied, so that
+// "20 °C".Convert("°F") returns "68 °F".  Similarly, values are converted
+// between logarithmic units (see Logarithmic) and their linear
+// equivalents, so that "30 dBm".Convert("W") returns "1 W".
+//
+// Values of one kind (see Kind) are not converted to units of another,
+// even if they reduce to the same primitive units: "5 Sv".Convert("Gy")
+// returns ("5 Gy", "Sv/Gy").  Use As to change a value's kind first.
+//
+// The result is expressed in wanted.Units().  Any scalar wanted applies,
+// as in m.Mul(Scalar(1000)), is not part of its units, and so is not
+// applied to the result: "1000 in".Convert(m.Mul(Scalar(1000))) returns
+// "25.4 m", not "25400 m" as earlier versions did.  Divide the result by
+// wanted.Value() for the number of such scaled units.
 func (a Value) Convert(wanted Maker) (result Value, remain Units) {
 	defer tracein("%q.Convert(%q)", a, wanted)()
-	if a.Units().Equal(wanted.Units()) {
+	wu := wanted.Units()
+	if a.Units().Equal(wu) {
 		result = a
 		return
 	}
-	rv := a.Div(wanted).Units().Reduce() // Divide out the wanted units
-	return wanted(a.S * rv.S), rv.U
+	if la, lw := a.U.logarithmic(), wu.logarithmic(); la != nil || lw != nil {
+		if r, ok := a.convertLog(la, wu, lw); ok {
+			return r, Units{}
+		}
+	}
+	d := a.U.dims().div(wu.dims()) // Divide out the wanted units
+	if len(d.t) > 0 {
+		return Value{S: a.S * d.f, U: wu}, d.units()
+	}
+	if !kindsAgree(a.U, wu) {
+		return Value{S: a.S * d.f, U: wu}, kindRemainder(a.U, wu)
+	}
+	from, to := offsets(a.U, wu)
+	result = Value{S: (a.S+from)*d.f - to, U: wu}
+	if wu.Kind() == nil {
+		result.U.K = a.U.K
+	}
+	if a.R != nil {
+		result = result.withRat(a.convertRat(wu))
+	}
+	return result, Units{}
 }
 
-// MustConvert calls a.Convert(wanted) and panics if this returns a
-// non-empty remainder, indicating the units are not conforming.
+// MustConvert calls a.Convert(wanted) and panics with a *ConversionError
+// if this returns a non-empty remainder, indicating the units are not
+// conforming.
 func MustConvert(a Qualified, wanted Maker) Value {
-	v := FromQualified(a)
-	result, remain := v.Convert(wanted)
-	if !remain.Empty() {
-		panic(fmt.Sprintf("Cannot convert %q to units %q (got: %q, remainder: %q)", a, wanted, result, remain))
+	result, err := FromQualified(a).ConvertErr(wanted)
+	if err != nil {
+		panic(err)
 	}
 	return result
 }
@@ -187,8 +349,9 @@ func MustConvert(a Qualified, wanted Maker) Value {
 func (a Value) Reduce() (v Value) {
 	defer tracein("%q.Reduce()", a)()
 	v = a.U.Reduce()
+	r := mulRat(a.R, v.R)
 	v.S *= a.S
-	return
+	return v.withRat(r)
 }
 
 // formatStr reconstructs a Printf-style format string from a fmt.State
@@ -238,5 +401,8 @@ func (a Value) String() string {
 func (a Value) Recip() (r Value) {
 	r.S = 1 / a.S
 	r.U = a.U.Recip()
+	if a.R != nil {
+		r = r.withRat(quoRat(big.NewRat(1, 1), a.R))
+	}
 	return r
 }
@@ -2,6 +2,7 @@ package unit_test
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/dnesting/unit"
@@ -35,7 +36,6 @@ func TestValueEqual(t *testing.T) {
 	}
 }
 
-/*
 func TestValueLess(t *testing.T) {
 	meter := unit.Primitive("m")
 	second := unit.Primitive("s")
@@ -59,7 +59,6 @@ func TestValueLess(t *testing.T) {
 		t.Errorf("%v should be NOT less than %v with incompatible units", a, b)
 	}
 }
-*/
 
 func TestMath(t *testing.T) {
 	meter := unit.Primitive("m")
@@ -176,6 +175,26 @@ func TestConvert(t *testing.T) {
 	}
 }
 
+func TestConvertScaled(t *testing.T) {
+	m := unit.Primitive("m")
+	in := unit.Derive("in", m(0.0254))
+	km := m.Mul(unit.Scalar(1000))
+
+	// The scalar of km is not part of its units, so the result is in m and
+	// equal to a, rather than scaled by 1000 a second time.
+	a := in(1000)
+	r, extra := a.Convert(km)
+	if !extra.Empty() || !r.Approx(a, 0.00001) {
+		t.Errorf("%q.Convert(%v) should give a value equal to %q, got %q (extra=%q)", a, km, a, r, extra)
+	}
+	if e := m(25.4); !r.Approx(e, 0.00001) || !r.Units().Equal(m.Units()) {
+		t.Errorf("%q.Convert(%v) should give %q, got %q", a, km, e, r)
+	}
+	if n := r.Value() / km.Value(); math.Abs(n-0.0254) > 1e-9 {
+		t.Errorf("%q should be 0.0254 of %v, got %v", r, km, n)
+	}
+}
+
 func TestString(t *testing.T) {
 	m := unit.Primitive("m")
 	s := unit.Primitive("s")
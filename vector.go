@@ -0,0 +1,160 @@
+package unit
+
+import "math"
+
+// Vector is a vector of components sharing the units U, such as a velocity
+// or a force.  Vectors usually have 2 or 3 components.  Operations
+// combining two vectors fail, returning false, if they have different
+// numbers of components.  Like Complex, vectors are assumed to be in
+// linear units.
+// C must not be modified once the Vector is in use.
+type Vector struct {
+	C []float64
+	U Units
+}
+
+// Vector creates a new vector with the given components in m's units.
+func (m Maker) Vector(components ...float64) Vector {
+	v := m(1)
+	c := make([]float64, len(components))
+	for i, x := range components {
+		c[i] = x * v.S
+	}
+	return Vector{C: c, U: v.U}
+}
+
+// Units returns the Units for v.
+func (v Vector) Units() Units { return v.U }
+
+// Len returns the number of components in v.
+func (v Vector) Len() int { return len(v.C) }
+
+// Component returns the ith component of v.
+func (v Vector) Component(i int) Value { return Value{S: v.C[i], U: v.U} }
+
+func (v Vector) String() string {
+	return DefaultFormatter.FormatVector(v)
+}
+
+// scale returns v with each component multiplied by f, in units u.
+func (v Vector) scale(f float64, u Units) Vector {
+	c := make([]float64, len(v.C))
+	for i, x := range v.C {
+		c[i] = x * f
+	}
+	return Vector{C: c, U: u}
+}
+
+// Convert converts every component of v into the units of wanted, as
+// described in Complex.Convert.  If the units are not conformable, remain
+// will be non-empty.
+func (v Vector) Convert(wanted Maker) (result Vector, remain Units) {
+	wu := wanted.Units()
+	if v.U.Equal(wu) {
+		return v, Units{}
+	}
+	f, u, remain := linearFactor(v.U, wu)
+	return v.scale(f, u), remain
+}
+
+func (a Vector) conform(b Vector) (Vector, bool) {
+	if a.U.Equal(b.U) {
+		return b, true
+	}
+	b, remain := b.Convert(a.U.Make)
+	return b, remain.Empty()
+}
+
+// Add adds a and b, returning the result in a's units.  If the units are
+// not equivalent, or a and b have different numbers of components, r will
+// be empty and ok will be false.
+func (a Vector) Add(b Vector) (r Vector, ok bool) {
+	if len(a.C) != len(b.C) {
+		return
+	}
+	if b, ok = a.conform(b); !ok {
+		return
+	}
+	r = Vector{C: make([]float64, len(a.C)), U: a.U}
+	for i := range a.C {
+		r.C[i] = a.C[i] + b.C[i]
+	}
+	return
+}
+
+// Sub subtracts b from a, returning the result in a's units.  If the units
+// are not equivalent, or a and b have different numbers of components, r
+// will be empty and ok will be false.
+func (a Vector) Sub(b Vector) (r Vector, ok bool) {
+	if len(a.C) != len(b.C) {
+		return
+	}
+	if b, ok = a.conform(b); !ok {
+		return
+	}
+	r = Vector{C: make([]float64, len(a.C)), U: a.U}
+	for i := range a.C {
+		r.C[i] = a.C[i] - b.C[i]
+	}
+	return
+}
+
+// Mul multiplies each component of a by b, returning the result.
+func (a Vector) Mul(b Qualified) Vector {
+	return a.scale(b.Value(), a.U.Mul(b.Units()))
+}
+
+// MulN multiplies each component of a by b, keeping a's units.
+func (a Vector) MulN(b float64) Vector {
+	return a.scale(b, a.U)
+}
+
+// Div divides each component of a by b, returning the result.
+func (a Vector) Div(b Qualified) Vector {
+	return a.scale(1/b.Value(), a.U.Div(b.Units()))
+}
+
+// DivN divides each component of a by b, keeping a's units.
+func (a Vector) DivN(b float64) Vector {
+	return a.scale(1/b, a.U)
+}
+
+// Dot returns the dot (scalar) product of a and b, whose units are the
+// product of their units.  If a and b have different numbers of
+// components, r will be empty and ok will be false.
+func (a Vector) Dot(b Vector) (r Value, ok bool) {
+	if len(a.C) != len(b.C) {
+		return
+	}
+	var s float64
+	for i := range a.C {
+		s += a.C[i] * b.C[i]
+	}
+	return Value{S: s, U: a.U.Mul(b.U)}, true
+}
+
+// Cross returns the cross product of the 3-component vectors a and b,
+// whose units are the product of their units.  If a or b does not have 3
+// components, r will be empty and ok will be false.
+func (a Vector) Cross(b Vector) (r Vector, ok bool) {
+	if len(a.C) != 3 || len(b.C) != 3 {
+		return
+	}
+	return Vector{
+		C: []float64{
+			a.C[1]*b.C[2] - a.C[2]*b.C[1],
+			a.C[2]*b.C[0] - a.C[0]*b.C[2],
+			a.C[0]*b.C[1] - a.C[1]*b.C[0],
+		},
+		U: a.U.Mul(b.U),
+	}, true
+}
+
+// Norm returns the Euclidean length of v.
+func (v Vector) Norm() Value {
+	var s float64
+	for _, x := range v.C {
+		s = math.Hypot(s, x)
+	}
+	return Value{S: s, U: v.U}
+}
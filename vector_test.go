@@ -0,0 +1,118 @@
+package unit_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/dnesting/unit"
+)
+
+func approxVector(a, b unit.Vector) bool {
+	if len(a.C) != len(b.C) || !a.U.Equal(b.U) {
+		return false
+	}
+	for i := range a.C {
+		if math.Abs(a.C[i]-b.C[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestVector(t *testing.T) {
+	m := unit.Primitive("m")
+	km := unit.Derive("km", m(1000))
+	s := unit.Primitive("s")
+	n := unit.Primitive("N")
+
+	a := m.Vector(1, 2, 3)
+	b := km.Vector(0.004, 0.005, 0.006)
+
+	if r, ok := a.Add(b); !ok || !approxVector(r, m.Vector(5, 7, 9)) {
+		t.Errorf("%v + %v should be (5, 7, 9) m, got %v (ok=%v)", a, b, r, ok)
+	}
+	if r, ok := a.Sub(b); !ok || !approxVector(r, m.Vector(-3, -3, -3)) {
+		t.Errorf("%v - %v should be (-3, -3, -3) m, got %v (ok=%v)", a, b, r, ok)
+	}
+	if r, ok := a.Add(s.Vector(1, 1, 1)); ok {
+		t.Errorf("adding m and s vectors should fail, got %v", r)
+	}
+	if r := a.Div(s(2)); !approxVector(r, m.Div(s).Vector(0.5, 1, 1.5)) {
+		t.Errorf("%v / 2 s should be (0.5, 1, 1.5) m/s, got %v", a, r)
+	}
+	if r := a.Mul(s(2)); !approxVector(r, m.Mul(s).Vector(2, 4, 6)) {
+		t.Errorf("%v × 2 s should be (2, 4, 6) m s, got %v", a, r)
+	}
+	if r := a.MulN(2).DivN(4); !approxVector(r, m.Vector(0.5, 1, 1.5)) {
+		t.Errorf("%v × 2 / 4 should be (0.5, 1, 1.5) m, got %v", a, r)
+	}
+
+	f := n.Vector(0, 0, 10)
+	if r, ok := f.Dot(a); !ok || !r.Approx(n.Mul(m)(30), 1e-9) || !r.U.Equal(n.Mul(m).Units()) {
+		t.Errorf("%v · %v should be 30 N m, got %v", f, a, r)
+	}
+	if r, ok := m.Vector(2, 0, 0).Cross(n.Vector(0, 10, 0)); !ok || !approxVector(r, m.Mul(n).Vector(0, 0, 20)) {
+		t.Errorf("(2, 0, 0) m × (0, 10, 0) N should be (0, 0, 20) N m, got %v", r)
+	}
+	if r := m.Vector(3, 4).Norm(); !r.Approx(m(5), 1e-9) {
+		t.Errorf("|(3, 4) m| should be 5 m, got %v", r)
+	}
+	if r := a.Component(1); !r.Equal(m(2)) {
+		t.Errorf("component 1 of %v should be 2 m, got %v", a, r)
+	}
+}
+
+func TestVectorConvert(t *testing.T) {
+	m := unit.Primitive("m")
+	km := unit.Derive("km", m(1000))
+	s := unit.Primitive("s")
+
+	v := km.Div(s).Vector(1, 2)
+	if r, remain := v.Convert(m.Div(s)); !remain.Empty() || !approxVector(r, m.Div(s).Vector(1000, 2000)) {
+		t.Errorf("%v should convert to (1000, 2000) m/s, got %v (remain=%q)", v, r, remain)
+	}
+	if r, remain := v.Convert(m); remain.Empty() {
+		t.Errorf("%v should not convert to m, got %v", v, r)
+	}
+
+	w := unit.Primitive("W")
+	dbw := unit.Logarithmic("dBW", w(1), 1, false)
+	if r, remain := w.Vector(1, 2).Convert(dbw); remain.Empty() {
+		t.Errorf("vectors should not convert to logarithmic units, got %v", r)
+	}
+	if r, remain := dbw.Vector(1, 2).Convert(w); remain.Empty() {
+		t.Errorf("vectors should not convert from logarithmic units, got %v", r)
+	}
+}
+
+func TestVectorMismatch(t *testing.T) {
+	m := unit.Primitive("m")
+	a, b := m.Vector(1, 2), m.Vector(1, 2, 3)
+	if r, ok := a.Add(b); ok {
+		t.Errorf("adding vectors of different lengths should fail, got %v", r)
+	}
+	if r, ok := a.Sub(b); ok {
+		t.Errorf("subtracting vectors of different lengths should fail, got %v", r)
+	}
+	if r, ok := a.Dot(b); ok {
+		t.Errorf("the dot product of vectors of different lengths should fail, got %v", r)
+	}
+	if r, ok := a.Cross(a); ok {
+		t.Errorf("the cross product of 2-component vectors should fail, got %v", r)
+	}
+}
+
+func ExampleVector() {
+	n := unit.Primitive("N")
+	m := unit.Primitive("m")
+
+	r := m.Vector(0.5, 0, 0)
+	f := n.Vector(0, 10, 0)
+	torque, _ := r.Cross(f)
+	fmt.Println(torque)
+	fmt.Println(f.Norm())
+	// Output:
+	// (0, 0, 5) N m
+	// 10 N
+}